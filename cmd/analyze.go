@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// Flag variables
+var (
+	analyzeLast     int
+	analyzeLanguage string
+	analyzeFile     string
+	analyzeTop      int
+	analyzeMin      int
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Analyze keystroke timing across your saved sessions",
+	Long: `Analyze the timing of every keystroke in your session history.
+
+Shows how long you take between keys (median, 90th and 99th percentile),
+the character pairs and triples that take you longest, and the characters
+you hesitate before. Only runs of correct keys on one line are timed, and
+pauses over 3 seconds are treated as breaks and left out.
+
+Examples:
+  syntaxrush analyze                 # Every recorded session
+  syntaxrush analyze --last 10       # The 10 most recent sessions
+  syntaxrush analyze --lang py       # Python sessions only
+  syntaxrush analyze --top 20 --min 5`,
+	Args: cobra.NoArgs,
+	Run:  runAnalyze,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+
+	analyzeCmd.Flags().IntVarP(&analyzeLast, "last", "n", 0, "Only analyze the most recent sessions (0 = all)")
+	analyzeCmd.Flags().StringVarP(&analyzeLanguage, "lang", "l", "", "Only include sessions in this language (go, py, js, cpp, ...)")
+	analyzeCmd.Flags().StringVarP(&analyzeFile, "file", "f", "", "Only include sessions of this file")
+	analyzeCmd.Flags().IntVar(&analyzeTop, "top", 10, "Number of bigrams, trigrams and characters to list")
+	analyzeCmd.Flags().IntVar(&analyzeMin, "min", 3, "Times a sequence must occur to be listed")
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) {
+	fmt.Println("⌨️  SyntaxRush Keystroke Analysis")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Println()
+
+	history, err := core.OpenDefaultHistory()
+	if err != nil {
+		fmt.Printf("❌ Error opening history: %v\n", err)
+		os.Exit(1)
+	}
+
+	records, err := history.Load()
+	if err != nil {
+		fmt.Printf("❌ Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+	records = core.FilterRecords(records, analyzeLanguage, analyzeFile)
+
+	// Sessions recorded before keystroke logging, or with only pasted
+	// text, have nothing to analyze
+	var streams [][]core.Keystroke
+	skipped := 0
+	for _, record := range records {
+		if record.Keystrokes == nil || len(record.Keystrokes.DelaysMS) == 0 {
+			skipped++
+			continue
+		}
+		streams = append(streams, record.Keystrokes.Keystrokes())
+	}
+	if analyzeLast > 0 && len(streams) > analyzeLast {
+		streams = streams[len(streams)-analyzeLast:]
+	}
+
+	if len(streams) == 0 {
+		fmt.Println("📈 No keystroke data recorded yet.")
+		if skipped > 0 {
+			fmt.Printf("   %d older sessions were recorded before keystroke timing was saved.\n", skipped)
+		}
+		fmt.Println()
+		fmt.Println("💡 Keystrokes are saved with every session from now on:")
+		fmt.Println("   syntaxrush practice go")
+		return
+	}
+
+	analysis := core.AnalyzeKeystrokes(streams, analyzeMin)
+
+	fmt.Printf("📈 %d sessions • %d keystrokes", len(streams), analysis.Keystrokes)
+	if skipped > 0 {
+		fmt.Printf(" • %d older sessions without keystroke data", skipped)
+	}
+	fmt.Println()
+	fmt.Println()
+
+	fmt.Println("⏱️  Time Between Keys:")
+	fmt.Printf("   • p50: %s │ p90: %s │ p99: %s\n",
+		formatLatency(analysis.P50), formatLatency(analysis.P90), formatLatency(analysis.P99))
+	fmt.Printf("   • %d gaps measured, %d pauses over 3s left out\n", analysis.Latencies, analysis.Pauses)
+	fmt.Println()
+
+	displayNgrams("🐢 Slowest Bigrams:", analysis.Bigrams)
+	displayNgrams("🐢 Slowest Trigrams:", analysis.Trigrams)
+	displayNgrams("🤔 Longest Hesitation Before:", analysis.Characters)
+
+	fmt.Println("💡 Practice the slowest sequences in your own code to speed them up")
+}
+
+// displayNgrams prints the first --top sequences of a list
+func displayNgrams(title string, speeds []core.NgramSpeed) {
+	fmt.Println(title)
+	if len(speeds) == 0 {
+		fmt.Printf("   Not enough data yet (sequences need %d occurrences)\n\n", analyzeMin)
+		return
+	}
+	if len(speeds) > analyzeTop {
+		speeds = speeds[:analyzeTop]
+	}
+	for _, speed := range speeds {
+		fmt.Printf("   %-6s %7s  (%d times)\n", showKeys(speed.Text), formatLatency(speed.Average), speed.Count)
+	}
+	fmt.Println()
+}
+
+// showKeys makes whitespace in a key sequence visible
+func showKeys(text string) string {
+	return strings.NewReplacer(" ", "␣", "\t", "→").Replace(text)
+}
+
+// formatLatency formats a key gap in milliseconds, or seconds when long
+func formatLatency(d time.Duration) string {
+	if d >= time.Second {
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
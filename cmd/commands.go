@@ -2,9 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"math"
+	"os"
 	"runtime"
+	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/theme"
+	"github.com/vamshi1188/SyntaxRush/ui"
 )
 
 var statsCmd = &cobra.Command{
@@ -18,19 +25,49 @@ This shows your historical data including:
 • Total practice time
 • Achievement progress
 • Muscle Power Indicator trends
-• Most practiced languages`,
+• Mistakes and speed per finger on your keyboard layout
+• Most practiced languages
+
+Use --chart for sparklines and line charts of WPM, accuracy and peak
+power over your recent sessions, with 7- and 30-day rolling averages.
+
+Examples:
+  syntaxrush stats
+  syntaxrush stats --chart --last 50
+  syntaxrush stats --chart --lang python`,
 	Run: runStats,
 }
 
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configure SyntaxRush settings",
-	Long: `Configure various SyntaxRush settings such as:
-• Audio preferences
-• Color themes
-• Difficulty levels
-• Achievement notifications`,
-	Run: runConfig,
+	Long: `Show or change persistent SyntaxRush settings.
+
+Settings are stored as JSON in your SyntaxRush config directory. Command
+line flags override them for a single run.
+
+Examples:
+  syntaxrush config                   # Show all settings
+  syntaxrush config get theme
+  syntaxrush config set theme light
+  syntaxrush config set volume 60
+  syntaxrush config set volume.keypress 0`,
+	Args: cobra.NoArgs,
+	Run:  runConfig,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print one setting",
+	Args:  cobra.ExactArgs(1),
+	Run:   runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change one setting",
+	Args:  cobra.ExactArgs(2),
+	Run:   runConfigSet,
 }
 
 var versionCmd = &cobra.Command{
@@ -40,10 +77,27 @@ var versionCmd = &cobra.Command{
 	Run:   runVersion,
 }
 
+// Flag variables
+var (
+	statsChart      bool
+	statsLast       int
+	statsLanguage   string
+	statsFile       string
+	statsChartWidth int
+)
+
 func init() {
 	rootCmd.AddCommand(statsCmd)
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
 	rootCmd.AddCommand(versionCmd)
+
+	statsCmd.Flags().BoolVarP(&statsChart, "chart", "c", false, "Show trend charts instead of totals")
+	statsCmd.Flags().IntVarP(&statsLast, "last", "n", 30, "Number of recent sessions to chart")
+	statsCmd.Flags().StringVarP(&statsLanguage, "lang", "l", "", "Only include sessions in this language (go, py, js, cpp, ...)")
+	statsCmd.Flags().StringVarP(&statsFile, "file", "f", "", "Only include sessions of this file")
+	statsCmd.Flags().IntVar(&statsChartWidth, "width", 60, "Chart width in characters")
 }
 
 func runStats(cmd *cobra.Command, args []string) {
@@ -51,58 +105,293 @@ func runStats(cmd *cobra.Command, args []string) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
-	// TODO: Implement stats storage and retrieval
+	history, err := core.OpenDefaultHistory()
+	if err != nil {
+		fmt.Printf("❌ Error opening history: %v\n", err)
+		os.Exit(1)
+	}
+
+	records, err := history.Load()
+	if err != nil {
+		fmt.Printf("❌ Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+	records = core.FilterRecords(records, statsLanguage, statsFile)
+
+	if len(records) == 0 {
+		fmt.Println("📈 No sessions recorded yet.")
+		fmt.Println()
+		fmt.Println("💡 To start tracking stats, begin a practice session:")
+		fmt.Println("   syntaxrush practice go")
+		return
+	}
+
+	definition, err := selectedWPMDefinition(loadConfig())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if statsChart {
+		displayStatsCharts(records, definition)
+		return
+	}
+
+	var totalTime time.Duration
+	var bestWPM, bestCPM, totalAccuracy float64
+	var fingerFury, onFire, momentum int
+	languages := make(map[string]int)
+
+	for _, record := range records {
+		totalTime += record.Duration()
+		if wpm, ok := record.WPMAs(definition); ok {
+			bestWPM = math.Max(bestWPM, wpm)
+		}
+		bestCPM = math.Max(bestCPM, record.CPM)
+		totalAccuracy += record.Accuracy
+		languages[record.Language]++
+
+		switch {
+		case record.MaxStreak >= 100:
+			fingerFury++
+		case record.MaxStreak >= 50:
+			onFire++
+		case record.MaxStreak >= 25:
+			momentum++
+		}
+	}
+
 	fmt.Println("📈 Session History:")
-	fmt.Println("   • Total Sessions: 0")
-	fmt.Println("   • Total Practice Time: 0h 0m")
-	fmt.Println("   • Best WPM: 0")
-	fmt.Println("   • Best CPM: 0")
-	fmt.Println("   • Average Accuracy: 0%")
+	fmt.Printf("   • Total Sessions: %d\n", len(records))
+	fmt.Printf("   • Total Practice Time: %dh %dm\n", int(totalTime.Hours()), int(totalTime.Minutes())%60)
+	fmt.Printf("   • Best WPM: %.1f (%s: %s)\n", bestWPM, definition, definition.Description())
+	fmt.Printf("   • Best CPM: %.1f\n", bestCPM)
+	fmt.Printf("   • Average Accuracy: %.1f%%\n", totalAccuracy/float64(len(records)))
 	fmt.Println()
 
 	fmt.Println("🏆 Achievements:")
-	fmt.Println("   • Finger Fury: 0 times")
-	fmt.Println("   • On Fire: 0 times")
-	fmt.Println("   • Zen Mode: 0 times")
+	fmt.Printf("   • Finger Fury: %d times\n", fingerFury)
+	fmt.Printf("   • On Fire: %d times\n", onFire)
+	fmt.Printf("   • Gaining Momentum: %d times\n", momentum)
 	fmt.Println()
 
+	displayFingerStats(records)
+
+	names := make([]string, 0, len(languages))
+	for name := range languages {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if languages[names[i]] != languages[names[j]] {
+			return languages[names[i]] > languages[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
 	fmt.Println("📁 Languages Practiced:")
-	fmt.Println("   • Go: 0 sessions")
-	fmt.Println("   • Python: 0 sessions")
-	fmt.Println("   • JavaScript: 0 sessions")
-	fmt.Println("   • C++: 0 sessions")
+	for _, name := range names {
+		fmt.Printf("   • %s: %d sessions\n", name, languages[name])
+	}
 	fmt.Println()
 
-	fmt.Println("💡 To start tracking stats, begin a practice session:")
-	fmt.Println("   syntaxrush practice go")
+	fmt.Println("💡 See your trends with: syntaxrush stats --chart")
+}
+
+// displayFingerStats breaks the keystrokes saved with each session down by
+// finger on the selected keyboard layout
+func displayFingerStats(records []core.SessionRecord) {
+	layout, err := selectedKeyboardLayout(loadConfig())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	var streams [][]core.Keystroke
+	for _, record := range records {
+		if record.Keystrokes != nil && len(record.Keystrokes.DelaysMS) > 0 {
+			streams = append(streams, record.Keystrokes.Keystrokes())
+		}
+	}
+	analysis := core.AnalyzeFingers(layout, streams)
+	table := ui.RenderFingerTable(analysis)
+	if table == nil {
+		return
+	}
+
+	fmt.Printf("🖐️  Fingers (%s layout, %d sessions with keystroke data):\n", layout.Name, len(streams))
+	for _, row := range table {
+		fmt.Printf("   %s\n", row)
+	}
+	left, right := analysis.Hand(core.LeftHand), analysis.Hand(core.RightHand)
+	if hands := left.Keys + right.Keys; hands > 0 {
+		fmt.Printf("   ✋ Left hand: %.0f%% of keys, %.1f%% accuracy │ Right hand: %.0f%% of keys, %.1f%% accuracy\n",
+			float64(left.Keys)/float64(hands)*100, left.Accuracy(), float64(right.Keys)/float64(hands)*100, right.Accuracy())
+	}
+	fmt.Println()
+}
+
+// displayStatsCharts renders trend charts for the most recent sessions.
+// WPM is charted under one definition, leaving out sessions that cannot be
+// converted to it.
+func displayStatsCharts(records []core.SessionRecord, definition core.WPMDefinition) {
+	t, err := resolveTheme()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	recent := lastRecords(records)
+
+	fmt.Printf("📈 Last %d sessions", len(recent))
+	if statsLanguage != "" {
+		fmt.Printf(" • language: %s", core.LanguageFromName(statsLanguage))
+	}
+	if statsFile != "" {
+		fmt.Printf(" • file: %s", statsFile)
+	}
+	fmt.Println()
+	fmt.Println()
+
+	var wpmRecords []core.SessionRecord
+	for _, record := range records {
+		if _, ok := record.WPMAs(definition); ok {
+			wpmRecords = append(wpmRecords, record)
+		}
+	}
+	wpm := func(r core.SessionRecord) float64 {
+		value, _ := r.WPMAs(definition)
+		return value
+	}
+
+	charts := []struct {
+		title   string
+		unit    string
+		value   func(core.SessionRecord) float64
+		records []core.SessionRecord
+	}{
+		{fmt.Sprintf("⚡ WPM (%s)", definition), "", wpm, wpmRecords},
+		{"🎯 Accuracy", "%", func(r core.SessionRecord) float64 { return r.Accuracy }, records},
+		{"💪 Peak Power", "", func(r core.SessionRecord) float64 { return r.PeakPower }, records},
+	}
+
+	now := time.Now()
+	for _, chart := range charts {
+		chartRecent := lastRecords(chart.records)
+		values := make([]float64, len(chartRecent))
+		trend := make([]float64, len(chartRecent))
+		for i, record := range chartRecent {
+			values[i] = chart.value(record)
+			// Rolling 7-day average as of each session, over all matching records
+			trend[i], _ = core.RollingAverage(chart.records, record.Timestamp, 7*24*time.Hour, chart.value)
+		}
+
+		avg7, count7 := core.RollingAverage(chart.records, now, 7*24*time.Hour, chart.value)
+		avg30, count30 := core.RollingAverage(chart.records, now, 30*24*time.Hour, chart.value)
+
+		fmt.Println(t.PaneTitle.Render(chart.title))
+		fmt.Println(ui.RenderLineChart(t, values, statsChartWidth, 4))
+		fmt.Println(t.ChartAxis.Render(" sessions│") + t.ChartLine.Render(ui.Sparkline(values)))
+		fmt.Println(t.ChartAxis.Render(" 7d trend│") + t.ChartLine.Render(ui.Sparkline(trend)))
+		fmt.Printf("   7-day avg: %s │ 30-day avg: %s\n",
+			formatAverage(avg7, count7, chart.unit), formatAverage(avg30, count30, chart.unit))
+		fmt.Println()
+	}
+}
+
+// lastRecords returns the records covered by --last
+func lastRecords(records []core.SessionRecord) []core.SessionRecord {
+	if statsLast > 0 && len(records) > statsLast {
+		return records[len(records)-statsLast:]
+	}
+	return records
+}
+
+// formatAverage formats a rolling average with the number of sessions it covers
+func formatAverage(avg float64, count int, unit string) string {
+	if count == 0 {
+		return "--"
+	}
+	return fmt.Sprintf("%.1f%s (%d sessions)", avg, unit, count)
 }
 
 func runConfig(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
 	fmt.Println("⚙️  SyntaxRush Configuration")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
-	// TODO: Implement configuration management
-	fmt.Println("🔊 Audio Settings:")
-	fmt.Println("   • Audio Enabled: true")
-	fmt.Println("   • Error Sound: enabled")
-	fmt.Println("   • Success Sound: enabled")
+	for _, key := range core.ConfigKeys() {
+		value, _ := config.Get(key)
+		fmt.Printf("   • %s: %s\n", key, value)
+	}
 	fmt.Println()
 
-	fmt.Println("🎨 Display Settings:")
-	fmt.Println("   • Theme: Dark")
-	fmt.Println("   • Color Feedback: enabled")
-	fmt.Println("   • Show MPI: enabled")
-	fmt.Println()
+	fmt.Printf("📁 Config file: %s\n", config.Path())
+	fmt.Println("💡 Change a setting with: syntaxrush config set <key> <value>")
+}
 
-	fmt.Println("💪 Difficulty Settings:")
-	fmt.Println("   • Level: Normal")
-	fmt.Println("   • Backspace: disabled")
-	fmt.Println("   • Leading Spaces: auto-skip")
-	fmt.Println()
+func runConfigGet(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	value, err := config.Get(args[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(value)
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) {
+	config, err := core.LoadConfig()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	key, value := args[0], args[1]
+
+	// Themes are validated here since custom themes are loaded by commands
+	if key == "theme" {
+		loadUserThemes()
+		if _, err := theme.Get(value); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if key == "sound_theme" {
+		loadUserSoundThemes()
+		if _, err := core.GetSoundTheme(value); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if key == "keyboard_layout" {
+		loadUserKeyboardLayouts()
+		if _, err := core.GetKeyboardLayout(value); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := config.Set(key, value); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.Save(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
 
-	fmt.Println("💡 Configuration management coming soon!")
-	fmt.Println("   Use flags for now: syntaxrush practice --mute")
+	fmt.Printf("✅ %s set to %s\n", key, value)
 }
 
 func runVersion(cmd *cobra.Command, args []string) {
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var (
+	keyboardLayoutName string
+	showKeyboard       bool
+)
+
+var layoutsCmd = &cobra.Command{
+	Use:   "layouts",
+	Short: "List keyboard layouts for finger statistics and the on-screen keyboard",
+	Long: `List the keyboard layouts that keystrokes are mapped to fingers with.
+
+Every key is assigned to the finger that presses it in standard touch
+typing, and shifted symbols also count a shift press by the other hand's
+pinky. The session summary and 'syntaxrush stats' break your mistakes and
+speed down by finger and report how often one finger has to type two keys
+in a row (same-finger bigrams).
+
+Built-in layouts are "qwerty" (the default), "dvorak" and "colemak".
+Custom layouts are JSON files in the layouts folder of your SyntaxRush
+config directory. Each row lists the characters of its keys from left to
+right, from the number row down (13, 13, 11 and 10 keys), with and without
+shift:
+
+  {
+    "name": "workman",
+    "rows":    ["` + "`" + `1234567890-=", "qdrwbjfup;[]\\", "ashtgyneoi'", "zxmcvkl,./"],
+    "shifted": ["~!@#$%^&*()_+", "QDRWBJFUP:{}|", "ASHTGYNEOI\"", "ZXMCVKL<>?"]
+  }
+
+Select a layout with --layout or: syntaxrush config set keyboard_layout <name>
+
+The layout is also drawn by the on-screen keyboard, which highlights the
+next key to press (and shift when it is needed), flashes keys you mistype
+and tints keys by how often you mistype them. Toggle it with Ctrl+K while
+typing, or show it from the start with --keyboard or:
+  syntaxrush config set show_keyboard true`,
+	Args: cobra.NoArgs,
+	Run:  runLayouts,
+}
+
+func init() {
+	rootCmd.AddCommand(layoutsCmd)
+
+	rootCmd.PersistentFlags().StringVar(&keyboardLayoutName, "layout", "", "Keyboard layout for finger statistics (see 'syntaxrush layouts')")
+	rootCmd.PersistentFlags().BoolVar(&showKeyboard, "keyboard", false, "Show the on-screen keyboard while typing (toggle with Ctrl+K)")
+}
+
+// layoutsDir returns the folder custom keyboard layouts are loaded from
+func layoutsDir() (string, error) {
+	dir, err := core.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "layouts"), nil
+}
+
+// loadUserKeyboardLayouts registers custom layouts, warning about files that fail to load
+func loadUserKeyboardLayouts() {
+	dir, err := layoutsDir()
+	if err != nil {
+		return
+	}
+	if _, err := core.LoadKeyboardLayoutDir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Some keyboard layouts could not be loaded: %v\n", err)
+	}
+}
+
+// selectedKeyboardLayoutName returns the layout chosen by flag, then config, then default
+func selectedKeyboardLayoutName(config *core.Config) string {
+	if keyboardLayoutName != "" {
+		return keyboardLayoutName
+	}
+	if name := config.Layout; name != "" {
+		return name
+	}
+	return core.DefaultKeyboardLayout
+}
+
+// selectedKeyboardLayout loads custom layouts and returns the selected one
+func selectedKeyboardLayout(config *core.Config) (*core.KeyboardLayout, error) {
+	loadUserKeyboardLayouts()
+	return core.GetKeyboardLayout(selectedKeyboardLayoutName(config))
+}
+
+// configureKeyboard applies the selected keyboard layout to a model and
+// shows the on-screen keyboard if asked by flag, then config
+func configureKeyboard(model *ui.Model, config *core.Config) error {
+	layout, err := selectedKeyboardLayout(config)
+	if err != nil {
+		return err
+	}
+	model.SetKeyboardLayout(layout)
+
+	show := showKeyboard
+	if !rootCmd.PersistentFlags().Changed("keyboard") {
+		show = config.ShowKeyboard
+	}
+	model.SetShowKeyboard(show)
+	return nil
+}
+
+func runLayouts(cmd *cobra.Command, args []string) {
+	loadUserKeyboardLayouts()
+	current := selectedKeyboardLayoutName(loadConfig())
+
+	fmt.Println("⌨️  Available Keyboard Layouts")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for _, name := range core.KeyboardLayoutNames() {
+		layout, err := core.GetKeyboardLayout(name)
+		if err != nil {
+			continue
+		}
+		marker := "  "
+		if name == current {
+			marker = "▶ "
+		}
+		fmt.Printf("%s%-10s %s\n", marker, name, layout.Rows[1])
+	}
+
+	if dir, err := layoutsDir(); err == nil {
+		fmt.Println()
+		fmt.Printf("💡 Custom layouts are loaded from %s\n", dir)
+	}
+}
@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// teamKeyEnv holds the team key when --key is not given
+const teamKeyEnv = "SYNTAXRUSH_TEAM_KEY"
+
+// Flag variables
+var (
+	exportOut      string
+	exportUser     string
+	exportLanguage string
+	teamKey        string
+
+	leaderboardDir        string
+	leaderboardBy         string
+	leaderboardLanguage   string
+	leaderboardDifficulty string
+	leaderboardTop        int
+	leaderboardUnverified bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export your results as a bundle for a team leaderboard",
+	Long: `Export the sessions in your history as a result bundle that teammates
+can merge with 'syntaxrush leaderboard'. Put bundles in a shared folder or
+a git repository; no server is needed.
+
+Every bundle carries a checksum, so bundles edited after export are
+rejected. With a team key (--key or the ` + teamKeyEnv + ` environment
+variable) the bundle is also signed, and a leaderboard merged with the same
+key only accepts bundles signed with it.
+
+Sessions that fail validation (see 'syntaxrush leaderboard --help') are
+left out of the bundle.
+
+Examples:
+  syntaxrush export                                # Writes <user>` + core.BundleExtension + `
+  syntaxrush export --out /shared/results          # Into a shared folder
+  syntaxrush export --user alice --key s3cret --lang go`,
+	Args: cobra.NoArgs,
+	Run:  runExport,
+}
+
+var leaderboardCmd = &cobra.Command{
+	Use:   "leaderboard",
+	Short: "Rank your team's exported results",
+	Long: `Merge the result bundles in a folder into team rankings: timed tests
+by length, practice sessions by snippet (the practiced code, shown by its
+file name) and every result by language. Each user appears once per ranking with their
+best result, scored in standard five-character words. Only results typed
+at one difficulty are ranked together.
+
+Every result is validated before it is ranked. Its keystrokes must look
+typed by hand: keys no closer than 10ms apart (bar the odd overlap), no
+bursts above 300 WPM, no machine-even timing and no pasted text. Replaying
+the keystrokes must also give the speed, accuracy and mistakes the result
+reports. Results recorded before keystrokes were kept cannot be verified
+and are left out unless --unverified is given.
+
+Examples:
+  syntaxrush leaderboard --dir /shared/results
+  syntaxrush leaderboard --dir ./results --by snippet --top 5
+  syntaxrush leaderboard --dir ./results --lang py --key s3cret
+  syntaxrush leaderboard --dir ./results --difficulty hard`,
+	Args: cobra.NoArgs,
+	Run:  runLeaderboard,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(leaderboardCmd)
+
+	exportCmd.Flags().StringVarP(&exportOut, "out", "o", "", "Bundle file or folder to write to (default <user>"+core.BundleExtension+")")
+	exportCmd.Flags().StringVarP(&exportUser, "user", "u", "", "Name to rank your results under (default your login name)")
+	exportCmd.Flags().StringVarP(&exportLanguage, "lang", "l", "", "Only export sessions in this language (go, py, js, cpp, ...)")
+	exportCmd.Flags().StringVar(&teamKey, "key", "", "Team key to sign the bundle with (default $"+teamKeyEnv+")")
+
+	leaderboardCmd.Flags().StringVar(&leaderboardDir, "dir", "", "Folder of result bundles, searched with its subfolders")
+	leaderboardCmd.Flags().StringVar(&leaderboardBy, "by", "all", "Rankings to show (all, duration, snippet, language)")
+	leaderboardCmd.Flags().StringVarP(&leaderboardLanguage, "lang", "l", "", "Only rank results in this language")
+	leaderboardCmd.Flags().StringVarP(&leaderboardDifficulty, "difficulty", "d", "normal", "Difficulty to rank results of (easy, normal, hard)")
+	leaderboardCmd.Flags().IntVar(&leaderboardTop, "top", 10, "Users to list in each ranking")
+	leaderboardCmd.Flags().StringVar(&teamKey, "key", "", "Only accept bundles signed with this team key (default $"+teamKeyEnv+")")
+	leaderboardCmd.Flags().BoolVar(&leaderboardUnverified, "unverified", false, "Also rank results recorded without keystrokes to verify")
+	leaderboardCmd.MarkFlagRequired("dir")
+}
+
+// selectedTeamKey returns the team key from the flag, then the environment
+func selectedTeamKey() string {
+	if teamKey != "" {
+		return teamKey
+	}
+	return os.Getenv(teamKeyEnv)
+}
+
+// defaultUserName returns the login name of the current user
+func defaultUserName() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	return "anonymous"
+}
+
+func runExport(cmd *cobra.Command, args []string) {
+	history, err := core.OpenDefaultHistory()
+	if err != nil {
+		fmt.Printf("❌ Error opening history: %v\n", err)
+		os.Exit(1)
+	}
+	records, err := history.Load()
+	if err != nil {
+		fmt.Printf("❌ Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+	records = core.FilterRecords(records, exportLanguage, "")
+
+	// Results that fail validation would be rejected by the leaderboard
+	valid := records[:0]
+	skipped := 0
+	for _, record := range records {
+		if err := record.Validate(); err != nil && err != core.ErrUnverifiable {
+			fmt.Printf("🚫 Skipping %s from %s: %v\n", filepath.Base(record.File), record.Timestamp.Format("2006-01-02 15:04"), err)
+			skipped++
+			continue
+		}
+		valid = append(valid, record)
+	}
+	records = valid
+
+	name := exportUser
+	if name == "" {
+		name = defaultUserName()
+	}
+
+	bundle := core.NewResultBundle(name, records, time.Now())
+	if len(bundle.Results) == 0 {
+		fmt.Println("📈 No sessions to export yet.")
+		fmt.Println("💡 Practice with: syntaxrush practice go")
+		return
+	}
+	key := selectedTeamKey()
+	if err := bundle.Seal(key); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// A folder gets a file named after the user
+	path := exportOut
+	if path == "" {
+		path = name + core.BundleExtension
+	} else if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, name+core.BundleExtension)
+	}
+	if err := bundle.WriteFile(path); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	signed := "checksummed"
+	if key != "" {
+		signed = "signed"
+	}
+	fmt.Printf("📦 Exported %d results for %s (%s) to %s\n", len(bundle.Results), name, signed, path)
+	if skipped > 0 {
+		fmt.Printf("⚠️  %d sessions failed validation and were left out\n", skipped)
+	}
+}
+
+func runLeaderboard(cmd *cobra.Command, args []string) {
+	switch leaderboardBy {
+	case "all", "duration", "snippet", "language":
+	default:
+		fmt.Printf("❌ invalid --by %q (choose from: all, duration, snippet, language)\n", leaderboardBy)
+		os.Exit(1)
+	}
+
+	difficulty, err := core.ParseDifficulty(leaderboardDifficulty)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	bundles, err := core.LoadResultBundles(leaderboardDir, selectedTeamKey())
+	if err != nil {
+		if bundles == nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "⚠️  Some bundles were rejected: %v\n", err)
+	}
+
+	rejected := core.ValidateResults(bundles, leaderboardUnverified)
+	rankings := core.RankTeam(bundles, leaderboardLanguage, difficulty)
+
+	fmt.Println("🏆 SyntaxRush Team Leaderboard")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("📦 Bundles: %d │ 👥 Users: %d │ 🎚️  Difficulty: %s\n", len(bundles), len(rankings.Users), difficulty)
+	displayRejectedResults(rejected)
+
+	nameWidth := len("user")
+	for _, name := range rankings.Users {
+		if len(name) > nameWidth {
+			nameWidth = len(name)
+		}
+	}
+
+	shown := 0
+	if leaderboardBy == "all" || leaderboardBy == "duration" {
+		shown += displayTeamRankings("⏱️ ", rankings.Durations, nameWidth)
+	}
+	if leaderboardBy == "all" || leaderboardBy == "snippet" {
+		shown += displayTeamRankings("📄", rankings.Snippets, nameWidth)
+	}
+	if leaderboardBy == "all" || leaderboardBy == "language" {
+		shown += displayTeamRankings("🌐", rankings.Languages, nameWidth)
+	}
+
+	if shown == 0 {
+		fmt.Println("\n📈 No results to rank yet.")
+		fmt.Printf("💡 Add one with: syntaxrush export --out %s\n", leaderboardDir)
+	}
+}
+
+// displayRejectedResults lists the results that failed validation and
+// counts those that could not be verified
+func displayRejectedResults(rejected []core.RejectedResult) {
+	unverified := 0
+	for _, r := range rejected {
+		if r.Err == core.ErrUnverifiable {
+			unverified++
+			continue
+		}
+		fmt.Printf("🚫 Rejected %s's %s from %s: %v\n", r.User, r.Result.Snippet, r.Result.Timestamp.Format("2006-01-02 15:04"), r.Err)
+	}
+	if unverified > 0 {
+		fmt.Printf("⚠️  %d results could not be verified and were left out (rank them with --unverified)\n", unverified)
+	}
+}
+
+// displayTeamRankings prints each ranking's top users and returns how many
+// rankings were printed
+func displayTeamRankings(icon string, rankings []core.TeamRanking, nameWidth int) int {
+	for _, ranking := range rankings {
+		fmt.Printf("\n%s %s:\n", icon, ranking.Title)
+		for i, entry := range ranking.Entries {
+			if i == leaderboardTop {
+				fmt.Printf("       … and %d more\n", len(ranking.Entries)-leaderboardTop)
+				break
+			}
+			attempts := "1 try"
+			if entry.Attempts > 1 {
+				attempts = fmt.Sprintf("%d tries", entry.Attempts)
+			}
+			fmt.Printf("   %2d. %-*s %6.1f WPM  %5.1f%%  %-10s %s  %s\n",
+				i+1, nameWidth, entry.User, entry.Best.WPM, entry.Best.Accuracy,
+				entry.Best.Language, entry.Best.Timestamp.Format("2006-01-02"), attempts)
+		}
+	}
+	return len(rankings)
+}
@@ -3,20 +3,21 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
 	"github.com/vamshi1188/SyntaxRush/ui"
 )
 
 // Flag variables
 var (
-	quick      bool
-	mute       bool
-	stats      bool
-	difficulty string
+	quick        bool
+	mute         bool
+	stats        bool
+	difficulty   string
+	paceWPM      int
+	playlistFile string
 )
 
 var practiceCmd = &cobra.Command{
@@ -31,7 +32,25 @@ Examples:
   syntaxrush practice filename            # Practice with main.go (from current directory)
   syntaxrush practice /path/to/filename   # Practice with absolute path
   syntaxrush practice go                 # Use Go sample
-  syntaxrush practice python --quick     # Quick Python practice`,
+  syntaxrush practice python --quick     # Quick Python practice
+  syntaxrush practice go --pace 60       # Metronome and pace marker at 60 WPM
+  syntaxrush practice go -d hard         # Type the indentation too
+  syntaxrush practice --playlist warmup.yaml
+
+A playlist is a YAML file of files, functions or line ranges typed back to
+back, with a combined summary at the end:
+
+  name: Weekly drill
+  entries:
+    - file: server.go              # Relative to the playlist file
+      symbol: handleRequest        # A function, method, type or class
+      pace: 50                     # Per-entry metronome target
+    - file: utils.py
+      lines: 10-40
+      repeat: 2
+    - file: go                     # Built-in samples work too
+      title: Go warm-up
+      keyboard: true               # Show the on-screen keyboard`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runPractice,
 }
@@ -43,7 +62,9 @@ func init() {
 	practiceCmd.Flags().BoolVarP(&quick, "quick", "q", false, "Skip welcome screen and start immediately")
 	practiceCmd.Flags().BoolVarP(&mute, "mute", "m", false, "Disable audio feedback")
 	practiceCmd.Flags().BoolVarP(&stats, "stats", "s", false, "Show detailed stats after session")
-	practiceCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "normal", "Set difficulty level (easy, normal, hard)")
+	practiceCmd.Flags().StringVarP(&difficulty, "difficulty", "d", "", "How much of each line is typed: easy, normal or hard (default from config)")
+	practiceCmd.Flags().IntVar(&paceWPM, "pace", 0, "Target WPM for the metronome and pace marker (default from config, 0 = off)")
+	practiceCmd.Flags().StringVar(&playlistFile, "playlist", "", "YAML playlist of files and snippets to type back to back")
 }
 
 func runPractice(cmd *cobra.Command, args []string) {
@@ -51,31 +72,61 @@ func runPractice(cmd *cobra.Command, args []string) {
 	model := ui.NewModel()
 
 	// Apply CLI flags
+	// Sound settings apply even when muted, so unmuting plays them
+	if err := configureAudio(model, nil, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
 	if mute {
 		model.SetAudioEnabled(false)
 	}
 
-	// Handle file argument
-	var filePath string
-	if len(args) > 0 {
-		filePath = args[0]
-	} else {
-		filePath = "go" // Default to Go sample
+	if err := configureDisplay(model); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
 	}
 
-	// Expand the file path (handles shortcuts and resolves paths)
-	resolvedPath := expandFilePath(filePath)
+	if err := configureScoring(model, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
 
-	// Try to load the file
-	if resolvedPath != "" {
-		err := model.LoadFile(resolvedPath)
-		if err != nil {
-			displayBanner()
-			fmt.Printf("❌ Error loading file '%s': %v\n", filePath, err)
-			fmt.Printf("💡 Make sure the file exists and is readable\n")
-			fmt.Printf("   Current directory: %s\n", getCurrentDir())
+	if err := configureKeyboard(model, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	d, err := selectedDifficulty(cmd, difficulty)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	model.SetDifficulty(d)
+
+	// Pacing comes from the flag, then config
+	pace := paceWPM
+	if !cmd.Flags().Changed("pace") {
+		pace = loadConfig().PaceWPM
+	}
+	model.SetPace(float64(pace))
+
+	// Record finished sessions; practice still works if history is unavailable
+	if history, err := core.OpenDefaultHistory(); err == nil {
+		model.SetHistory(history)
+	}
+	if files, err := core.OpenDefaultFileList(); err == nil {
+		model.SetFileList(files)
+	}
+
+	// A playlist replaces the file argument
+	if playlistFile != "" {
+		if len(args) > 0 {
+			fmt.Println("❌ Give either a file or --playlist, not both")
 			os.Exit(1)
 		}
+		loadPlaylist(model, playlistFile)
+	} else {
+		loadPracticeFile(model, args)
 	}
 
 	// Start directly if quick flag is set
@@ -105,70 +156,6 @@ func runPractice(cmd *cobra.Command, args []string) {
 	finalModel.(*ui.Model).Cleanup()
 }
 
-// expandFilePath expands shortcuts and handles relative/absolute paths
-func expandFilePath(input string) string {
-	// Handle built-in sample shortcuts first
-	switch strings.ToLower(input) {
-	case "go", "sample.go":
-		return findAssetFile("sample.go")
-	case "py", "python", "sample.py":
-		return findAssetFile("sample.py")
-	case "js", "javascript", "sample.js":
-		return findAssetFile("sample.js")
-	case "cpp", "c++", "sample.cpp":
-		return findAssetFile("sample.cpp")
-	default:
-		// For regular file paths, check if file exists
-		if filepath.IsAbs(input) {
-			// Absolute path - use as-is
-			return input
-		}
-
-		// Relative path - resolve from current directory
-		if absPath, err := filepath.Abs(input); err == nil {
-			return absPath
-		}
-
-		// If all else fails, return input as-is
-		return input
-	}
-}
-
-// findAssetFile finds the asset file from the binary's location or installation
-func findAssetFile(filename string) string {
-	// Get the directory where the binary is located
-	execPath, err := os.Executable()
-	if err != nil {
-		// Fallback to relative path
-		return filepath.Join("assets", filename)
-	}
-
-	execDir := filepath.Dir(execPath)
-
-	// Try different possible locations for assets
-	possiblePaths := []string{
-		// Assets in same directory as binary (for development)
-		filepath.Join(execDir, "assets", filename),
-		// Assets relative to binary (for installed version)
-		filepath.Join(execDir, "..", "share", "syntaxrush", "assets", filename),
-		// System-wide assets
-		filepath.Join("/usr", "share", "syntaxrush", "assets", filename),
-		// Local share
-		filepath.Join("/usr", "local", "share", "syntaxrush", "assets", filename),
-		// Fallback to current directory
-		filepath.Join("assets", filename),
-	}
-
-	for _, path := range possiblePaths {
-		if _, err := os.Stat(path); err == nil {
-			return path
-		}
-	}
-
-	// If no asset file found, return the relative path as fallback
-	return filepath.Join("assets", filename)
-}
-
 // displayBanner shows the SyntaxRush banner
 func displayBanner() {
 	fmt.Println("🚀 SyntaxRush - Elite Code Typing Trainer")
@@ -190,7 +177,8 @@ func displayFinalStats(model *ui.Model) {
 
 	fmt.Println("\n🏆 Session Complete!")
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Printf("⚡ WPM: %.1f\n", stats.WPM)
+	fmt.Printf("⚡ WPM: %.1f (%s: %s)\n", stats.WPM, stats.WPMDefinition, stats.WPMDefinition.Description())
+	fmt.Printf("🚀 Raw WPM: %.1f │ Net WPM: %.1f │ KPM: %.0f\n", stats.RawWPM, stats.NetWPM, stats.KPM)
 	fmt.Printf("📊 CPM: %.1f\n", stats.CPM)
 	fmt.Printf("🎯 Accuracy: %.1f%%\n", stats.Accuracy)
 	fmt.Printf("⏱️  Duration: %v\n", stats.TotalTime)
@@ -200,3 +188,52 @@ func displayFinalStats(model *ui.Model) {
 		fmt.Printf("💪 Final Power: %.0f%%\n", power)
 	}
 }
+
+// loadPracticeFile loads the file argument, or the Go sample without one
+func loadPracticeFile(model *ui.Model, args []string) {
+	var filePath string
+	if len(args) > 0 {
+		filePath = args[0]
+	} else {
+		filePath = "go" // Default to Go sample
+	}
+
+	// Expand the file path (handles shortcuts and resolves paths)
+	resolvedPath := core.ExpandFilePath(filePath)
+
+	// Try to load the file
+	if resolvedPath != "" {
+		err := model.LoadFile(resolvedPath)
+		if err != nil {
+			displayBanner()
+			fmt.Printf("❌ Error loading file '%s': %v\n", filePath, err)
+			fmt.Printf("💡 Make sure the file exists and is readable\n")
+			fmt.Printf("   Current directory: %s\n", getCurrentDir())
+			os.Exit(1)
+		}
+	}
+}
+
+// loadPlaylist loads a playlist and every entry in it
+func loadPlaylist(model *ui.Model, path string) {
+	playlist, err := core.LoadPlaylist(core.ExpandFilePath(path))
+	if err != nil {
+		displayBanner()
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if err := model.SetPlaylist(playlist); err != nil {
+		displayBanner()
+		fmt.Printf("❌ Error loading playlist '%s': %v\n", path, err)
+		os.Exit(1)
+	}
+}
+
+// selectedDifficulty returns the difficulty from the --difficulty flag,
+// then config
+func selectedDifficulty(cmd *cobra.Command, flag string) (core.Difficulty, error) {
+	if cmd.Flags().Changed("difficulty") {
+		return core.ParseDifficulty(flag)
+	}
+	return core.ParseDifficulty(loadConfig().Difficulty)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var wpmDefinition string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&wpmDefinition, "wpm", "", "How WPM counts words: "+strings.Join(core.WPMDefinitionNames(), ", ")+" (default from config)")
+}
+
+// selectedWPMDefinition returns the WPM definition chosen by flag, then config
+func selectedWPMDefinition(config *core.Config) (core.WPMDefinition, error) {
+	name := wpmDefinition
+	if name == "" {
+		name = config.WPMDefinition
+	}
+	if name == "" {
+		return core.DefaultWPMDefinition, nil
+	}
+	return core.ParseWPMDefinition(name)
+}
+
+// configureScoring applies the selected WPM definition to a model
+func configureScoring(model *ui.Model, config *core.Config) error {
+	definition, err := selectedWPMDefinition(config)
+	if err != nil {
+		return err
+	}
+	model.SetWPMDefinition(definition)
+	return nil
+}
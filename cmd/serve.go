@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/web"
+)
+
+// Flag variables
+var (
+	servePort int
+	serveHost string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [file]",
+	Short: "Practice in your browser with a locally served web UI",
+	Long: `Serve the SyntaxRush typing UI on a local port.
+
+The browser talks to the same session engine, metrics and Muscle Power
+Indicator as the terminal UI. Finished sessions are recorded to your
+history, which can be browsed with charts from the History tab.
+
+The browser can only load the file given here and the built-in samples,
+and requests must be addressed to the host and port being served.
+
+Examples:
+  syntaxrush serve                     # Go sample on http://localhost:8080
+  syntaxrush serve --port 9000 main.go # Practice main.go on port 9000`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().IntVarP(&servePort, "port", "p", 8080, "Port to listen on")
+	serveCmd.Flags().StringVar(&serveHost, "host", "127.0.0.1", "Address to bind to")
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	filePath := "go"
+	if len(args) > 0 {
+		filePath = args[0]
+	}
+
+	// Validate the starting file before opening the port
+	if _, err := core.NewParser().ParseFile(core.ExpandFilePath(filePath)); err != nil {
+		fmt.Printf("❌ Error loading file '%s': %v\n", filePath, err)
+		os.Exit(1)
+	}
+
+	history, err := core.OpenDefaultHistory()
+	if err != nil {
+		fmt.Printf("⚠️  Session history disabled: %v\n", err)
+		history = nil
+	}
+
+	definition, err := selectedWPMDefinition(loadConfig())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	server := web.NewServer(filePath, core.ExpandFilePath, history)
+	server.SetWPMDefinition(definition)
+	addr := net.JoinHostPort(serveHost, strconv.Itoa(servePort))
+	server.SetAddress(addr)
+
+	displayBanner()
+	fmt.Printf("🌐 Serving on http://%s\n", addr)
+	fmt.Println("   Press Ctrl+C to stop")
+
+	if err := http.ListenAndServe(addr, server.Handler()); err != nil {
+		fmt.Printf("❌ Server error: %v\n", err)
+		os.Exit(1)
+	}
+}
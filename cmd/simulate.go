@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var (
+	simulateInput      string
+	simulateDelay      time.Duration
+	simulateDifficulty string
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate [file]",
+	Short: "Replay a keystroke script against a file without a terminal",
+	Long: `Run a headless typing session driven by a keystroke script and print
+the final statistics as JSON.
+
+The script is typed verbatim: every character is one keystroke and each
+newline presses Enter. A few tokens are recognised for keys that cannot
+appear in a plain text file:
+  {bs}          Backspace
+  {tab}         Tab
+  {wait:DUR}    Advance the simulated clock by DUR (e.g. {wait:2s})
+
+Time is simulated, so results are deterministic and a long script runs as
+fast as the engine allows. The config file is not read: settings such as
+--wpm, --layout and --difficulty come from flags or their defaults, so a
+script gives the same result for everyone. With --audio-out session.wav
+the sounds of the run are recorded at their simulated times.
+
+Examples:
+  syntaxrush simulate --input keys.txt main.go
+  syntaxrush simulate --input keys.txt --delay 80ms go
+  syntaxrush simulate --input keys.txt --difficulty hard --wpm standard go
+  syntaxrush simulate --input keys.txt --sound-theme mechanical --audio-out run.wav go`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runSimulate,
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+
+	simulateCmd.Flags().StringVarP(&simulateInput, "input", "i", "", "Keystroke script to replay (required)")
+	simulateCmd.Flags().DurationVar(&simulateDelay, "delay", 150*time.Millisecond, "Simulated time between keystrokes")
+	simulateCmd.Flags().StringVarP(&simulateDifficulty, "difficulty", "d", "normal", "How much of each line is typed: easy, normal or hard")
+	simulateCmd.MarkFlagRequired("input")
+}
+
+// simulationResult is the JSON document printed by the simulate command
+type simulationResult struct {
+	File            string                 `json:"file"`
+	Completed       bool                   `json:"completed"`
+	Keystrokes      int                    `json:"keystrokes"`
+	DurationMS      int64                  `json:"duration_ms"`
+	WPM             float64                `json:"wpm"`
+	WPMDefinition   core.WPMDefinition     `json:"wpm_definition"`
+	RawWPM          float64                `json:"raw_wpm"`
+	NetWPM          float64                `json:"net_wpm"`
+	KPM             float64                `json:"kpm"`
+	CPM             float64                `json:"cpm"`
+	Accuracy        float64                `json:"accuracy"`
+	TotalMistakes   int                    `json:"total_mistakes"`
+	TotalCharacters int                    `json:"total_characters"`
+	LinesCompleted  int                    `json:"lines_completed"`
+	Lines           []lineReport           `json:"lines"`
+	SlowestLines    []lineReport           `json:"slowest_lines"`
+	ErrorProneLines []lineReport           `json:"error_prone_lines"`
+	MPI             map[string]interface{} `json:"mpi"`
+	WallTimeMS      float64                `json:"wall_time_ms"`
+}
+
+// hotspotLines is how many slowest and most error-prone lines are reported
+const hotspotLines = 5
+
+// lineReport is the timing and accuracy of one typed line
+type lineReport struct {
+	Line         int     `json:"line"` // 1-based line number
+	Code         string  `json:"code"`
+	Typed        string  `json:"typed"`
+	StartMS      int64   `json:"start_ms"`
+	EndMS        int64   `json:"end_ms"`
+	HesitationMS int64   `json:"hesitation_ms"`
+	TimeMS       int64   `json:"time_ms"`
+	WPM          float64 `json:"wpm"`
+	Mistakes     int     `json:"mistakes"`
+	Accuracy     float64 `json:"accuracy"`
+}
+
+// lineReports converts line statistics for the JSON result
+func lineReports(lines []core.LineStats) []lineReport {
+	reports := make([]lineReport, len(lines))
+	for i, line := range lines {
+		reports[i] = lineReport{
+			Line:         line.Line + 1,
+			Code:         line.Original,
+			Typed:        line.UserInput,
+			StartMS:      line.Start.Milliseconds(),
+			EndMS:        line.End.Milliseconds(),
+			HesitationMS: line.Hesitation.Milliseconds(),
+			TimeMS:       line.TimeSpent.Milliseconds(),
+			WPM:          line.WPM,
+			Mistakes:     line.Mistakes,
+			Accuracy:     line.Accuracy,
+		}
+	}
+	return reports
+}
+
+func runSimulate(cmd *cobra.Command, args []string) {
+	script, err := os.ReadFile(simulateInput)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error reading script '%s': %v\n", simulateInput, err)
+		os.Exit(1)
+	}
+
+	filePath := "go"
+	if len(args) > 0 {
+		filePath = args[0]
+	}
+
+	keys, err := parseKeyScript(string(script))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error parsing script '%s': %v\n", simulateInput, err)
+		os.Exit(1)
+	}
+
+	// Drive the session on a simulated clock so runs are reproducible
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	model, err := newSimulation(filePath, func() time.Time { return now })
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	defer model.Cleanup()
+
+	result := runScript(model, filePath, keys, simulateDelay, &now)
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error writing result: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runScript types keys into a started session, advancing the simulated
+// clock at now by delay before each key, and returns the results
+func runScript(model *ui.Model, filePath string, keys []scriptKey, delay time.Duration, now *time.Time) simulationResult {
+	wallStart := time.Now()
+	keystrokes := 0
+	for _, key := range keys {
+		if model.IsSessionComplete() {
+			break
+		}
+		if key.wait > 0 {
+			*now = now.Add(key.wait)
+			continue
+		}
+		*now = now.Add(delay)
+		model.Update(key.msg)
+		keystrokes++
+	}
+	wallTime := time.Since(wallStart)
+
+	stats := model.GetFinalStats()
+	lineStats := model.Session().LineStats()
+	return simulationResult{
+		File:            filePath,
+		Completed:       model.IsSessionComplete(),
+		Keystrokes:      keystrokes,
+		DurationMS:      stats.TotalTime.Milliseconds(),
+		WPM:             stats.WPM,
+		WPMDefinition:   stats.WPMDefinition,
+		RawWPM:          stats.RawWPM,
+		NetWPM:          stats.NetWPM,
+		KPM:             stats.KPM,
+		CPM:             stats.CPM,
+		Accuracy:        stats.Accuracy,
+		TotalMistakes:   stats.TotalMistakes,
+		TotalCharacters: stats.TotalCharacters,
+		LinesCompleted:  stats.LinesCompleted,
+		Lines:           lineReports(lineStats),
+		SlowestLines:    lineReports(core.SlowestLines(lineStats, hotspotLines)),
+		ErrorProneLines: lineReports(core.ErrorProneLines(lineStats, hotspotLines)),
+		MPI:             model.GetMPIStats(),
+		WallTimeMS:      float64(wallTime.Microseconds()) / 1000,
+	}
+}
+
+// newSimulation loads a file into a started session timed by clock.
+// Settings come from flags and defaults only, never the config file, so a
+// script gives the same result whoever runs it.
+func newSimulation(filePath string, clock core.Clock) (*ui.Model, error) {
+	difficulty, err := core.ParseDifficulty(simulateDifficulty)
+	if err != nil {
+		return nil, err
+	}
+	config := core.DefaultConfig()
+
+	model := ui.NewModel()
+	model.SetClock(clock)
+
+	// Sounds are only produced when recording them with --audio-out
+	if audioOut == "" {
+		model.SetAudioEnabled(false)
+	} else if err := configureAudio(model, clock, config); err != nil {
+		model.Cleanup()
+		return nil, err
+	}
+
+	if err := configureScoring(model, config); err != nil {
+		model.Cleanup()
+		return nil, err
+	}
+	if err := configureKeyboard(model, config); err != nil {
+		model.Cleanup()
+		return nil, err
+	}
+	model.SetDifficulty(difficulty)
+
+	if err := model.LoadFile(core.ExpandFilePath(filePath)); err != nil {
+		model.Cleanup()
+		return nil, fmt.Errorf("error loading file '%s': %v", filePath, err)
+	}
+	model.StartPracticeDirectly()
+	return model, nil
+}
+
+// scriptKey is a single step of a keystroke script: a key press or a pause
+type scriptKey struct {
+	msg  tea.KeyMsg
+	wait time.Duration
+}
+
+// parseKeyScript converts script text into key presses and pauses
+func parseKeyScript(script string) ([]scriptKey, error) {
+	var keys []scriptKey
+
+	for i := 0; i < len(script); {
+		rest := script[i:]
+
+		switch {
+		case strings.HasPrefix(rest, "{bs}"):
+			keys = append(keys, scriptKey{msg: tea.KeyMsg{Type: tea.KeyBackspace}})
+			i += len("{bs}")
+		case strings.HasPrefix(rest, "{tab}"):
+			keys = append(keys, scriptKey{msg: tea.KeyMsg{Type: tea.KeyTab}})
+			i += len("{tab}")
+		case strings.HasPrefix(rest, "{wait:"):
+			end := strings.Index(rest, "}")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated wait token at offset %d", i)
+			}
+			wait, err := time.ParseDuration(rest[len("{wait:"):end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid wait token at offset %d: %v", i, err)
+			}
+			keys = append(keys, scriptKey{wait: wait})
+			i += end + 1
+		case script[i] == '\n':
+			keys = append(keys, scriptKey{msg: tea.KeyMsg{Type: tea.KeyEnter}})
+			i++
+		case script[i] == '\r':
+			// Ignore carriage returns from scripts saved with CRLF endings
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			keys = append(keys, scriptKey{msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}})
+			i += size
+		}
+	}
+
+	return keys, nil
+}
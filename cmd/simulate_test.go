@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+func TestParseKeyScript(t *testing.T) {
+	runes := func(r rune) scriptKey {
+		return scriptKey{msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}}
+	}
+	enter := scriptKey{msg: tea.KeyMsg{Type: tea.KeyEnter}}
+
+	tests := []struct {
+		name   string
+		script string
+		want   []scriptKey
+	}{
+		{"plain text", "ab", []scriptKey{runes('a'), runes('b')}},
+		{"newline presses enter", "a\n", []scriptKey{runes('a'), enter}},
+		{"carriage returns are ignored", "a\r\nb", []scriptKey{runes('a'), enter, runes('b')}},
+		{"backspace", "a{bs}", []scriptKey{runes('a'), {msg: tea.KeyMsg{Type: tea.KeyBackspace}}}},
+		{"tab", "{tab}x", []scriptKey{{msg: tea.KeyMsg{Type: tea.KeyTab}}, runes('x')}},
+		{"wait", "{wait:1.5s}a", []scriptKey{{wait: 1500 * time.Millisecond}, runes('a')}},
+		{"multi-byte characters", "é→", []scriptKey{runes('é'), runes('→')}},
+		{"unknown braces are typed", "{}", []scriptKey{runes('{'), runes('}')}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyScript(tt.script)
+			if err != nil {
+				t.Fatalf("parseKeyScript(%q) returned error: %v", tt.script, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseKeyScript(%q) = %d keys, want %d", tt.script, len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].wait != tt.want[i].wait || got[i].msg.String() != tt.want[i].msg.String() || got[i].msg.Type != tt.want[i].msg.Type {
+					t.Errorf("key %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseKeyScriptErrors(t *testing.T) {
+	for _, script := range []string{"{wait:2s", "{wait:soon}"} {
+		if _, err := parseKeyScript(script); err == nil {
+			t.Errorf("parseKeyScript(%q) returned no error", script)
+		}
+	}
+}
+
+func TestRunScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("func f() {\n    return\n}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		script    string
+		completed bool
+		mistakes  int
+		duration  time.Duration
+	}{
+		{"clean run", "func f() {\nreturn\n}\n", true, 0, 20 * 100 * time.Millisecond},
+		{"mistake and backspace", "func g() {\n{bs}return\n}\n", true, 1, 21 * 100 * time.Millisecond},
+		{"wait adds time", "func f() {\n{wait:2s}return\n}\n", true, 0, 20*100*time.Millisecond + 2*time.Second},
+		{"unfinished", "func f() {\nret", false, 0, 14 * 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseKeyScript(tt.script)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			model := ui.NewModel()
+			defer model.Cleanup()
+			now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+			model.SetClock(func() time.Time { return now })
+			model.SetAudioEnabled(false)
+			if err := model.LoadFile(path); err != nil {
+				t.Fatal(err)
+			}
+			model.StartPracticeDirectly()
+
+			result := runScript(model, path, keys, 100*time.Millisecond, &now)
+			if result.Completed != tt.completed {
+				t.Errorf("completed = %v, want %v", result.Completed, tt.completed)
+			}
+			if result.TotalMistakes != tt.mistakes {
+				t.Errorf("mistakes = %d, want %d", result.TotalMistakes, tt.mistakes)
+			}
+			if got := time.Duration(result.DurationMS) * time.Millisecond; got != tt.duration {
+				t.Errorf("duration = %s, want %s", got, tt.duration)
+			}
+		})
+	}
+}
+
+func TestSimulationIgnoresConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte("func f() {\n    return x + 1\n}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := parseKeyScript("func f() {\nreturm{bs}n x + 1\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate runs the script with only the given config file present
+	simulate := func(config string) string {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		if config != "" {
+			if err := os.MkdirAll(filepath.Join(dir, "syntaxrush"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, "syntaxrush", "config.json"), []byte(config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+		model, err := newSimulation(path, func() time.Time { return now })
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer model.Cleanup()
+
+		result := runScript(model, path, keys, 100*time.Millisecond, &now)
+		result.WallTimeMS = 0
+		output, err := json.Marshal(result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(output)
+	}
+
+	want := simulate("")
+	config := `{"wpm_definition": "tokens", "keyboard_layout": "dvorak", "difficulty": "hard", "volume": 0}`
+	if got := simulate(config); got != want {
+		t.Errorf("with a config file:\n%s\nwant the same as without:\n%s", got, want)
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var (
+	soundThemeName string
+	audioOut       string
+)
+
+var soundsCmd = &cobra.Command{
+	Use:   "sounds",
+	Short: "List and preview sound themes",
+	Long: `List and preview the available sound themes.
+
+Sound themes choose what plays on each event: keypress, error,
+line_complete, achievement and session_complete. Built-in themes are
+"classic" (the default: no key clicks), "mechanical" (adds mechanical
+keyboard clicks) and "minimal" (only the error cue).
+
+Custom sound themes are JSON files in the sounds folder of your SyntaxRush
+config directory. Each sound is a list of synthesized tones or a WAV file
+(8- or 16-bit PCM, relative to the theme file), with an optional volume in
+percent. An empty sound ({}) silences an event:
+
+  {
+    "name": "thock",
+    "base": "mechanical",
+    "sounds": {
+      "keypress":      {"file": "thock.wav", "volume": 70},
+      "line_complete": {"tones": [{"frequency": 880, "duration_ms": 60}]},
+      "achievement":   {}
+    }
+  }
+
+Volumes are set per event in percent:
+  syntaxrush config set volume 80
+  syntaxrush config set volume.keypress 40
+
+Sounds can be written to a WAV file instead of the sound card with
+--audio-out file.wav, or discarded with --audio-out null.`,
+}
+
+var soundsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available sound themes",
+	Args:  cobra.NoArgs,
+	Run:   runSoundsList,
+}
+
+var soundsPreviewCmd = &cobra.Command{
+	Use:   "preview [name]",
+	Short: "Play every sound of a sound theme",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runSoundsPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(soundsCmd)
+	soundsCmd.AddCommand(soundsListCmd)
+	soundsCmd.AddCommand(soundsPreviewCmd)
+
+	rootCmd.PersistentFlags().StringVar(&soundThemeName, "sound-theme", "", "Sound theme (see 'syntaxrush sounds list')")
+	rootCmd.PersistentFlags().StringVar(&audioOut, "audio-out", "", "Write sounds to a WAV file, or 'null' to discard them")
+}
+
+// soundsDir returns the folder custom sound themes are loaded from
+func soundsDir() (string, error) {
+	dir, err := core.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "sounds"), nil
+}
+
+// loadUserSoundThemes registers custom sound themes, warning about files that fail to load
+func loadUserSoundThemes() {
+	dir, err := soundsDir()
+	if err != nil {
+		return
+	}
+	if _, err := core.LoadSoundThemeDir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Some sound themes could not be loaded: %v\n", err)
+	}
+}
+
+// selectedSoundThemeName returns the sound theme chosen by flag, then config, then default
+func selectedSoundThemeName(config *core.Config) string {
+	if soundThemeName != "" {
+		return soundThemeName
+	}
+	if name := config.SoundTheme; name != "" {
+		return name
+	}
+	return core.DefaultSoundTheme
+}
+
+// newAudioManager creates an audio manager for the --audio-out target. Sounds
+// recorded to a WAV file are positioned using clock.
+func newAudioManager(clock core.Clock) (*core.AudioManager, error) {
+	switch audioOut {
+	case "":
+		return core.NewAudioManager()
+	case "null":
+		return core.NewAudioManagerWithOutput(&core.NullOutput{}), nil
+	default:
+		return core.NewAudioManagerWithOutput(core.NewWAVOutput(audioOut, clock)), nil
+	}
+}
+
+// applySoundSettings sets the named sound theme and the configured volumes
+func applySoundSettings(audio *core.AudioManager, name string, config *core.Config) error {
+	loadUserSoundThemes()
+
+	t, err := core.GetSoundTheme(name)
+	if err != nil {
+		return err
+	}
+	if err := audio.SetSoundTheme(t); err != nil {
+		return err
+	}
+
+	audio.SetVolume(float64(config.Volume) / 100)
+	for _, event := range core.SoundEvents {
+		audio.SetEventVolume(event, float64(config.EventVolume(event))/100)
+	}
+	return nil
+}
+
+// configureAudio applies the audio output, sound theme and volumes to a model
+func configureAudio(model *ui.Model, clock core.Clock, config *core.Config) error {
+	audio := model.Audio()
+	if audioOut != "" {
+		var err error
+		if audio, err = newAudioManager(clock); err != nil {
+			return err
+		}
+		model.SetAudio(audio)
+	}
+
+	// Without a sound card the model falls back to the terminal bell
+	if audio == nil {
+		return nil
+	}
+	return applySoundSettings(audio, selectedSoundThemeName(config), config)
+}
+
+func runSoundsList(cmd *cobra.Command, args []string) {
+	loadUserSoundThemes()
+	current := selectedSoundThemeName(loadConfig())
+
+	fmt.Println("🔊 Available Sound Themes")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	for _, name := range core.SoundThemeNames() {
+		marker := "  "
+		if name == current {
+			marker = "▶ "
+		}
+		fmt.Printf("%s%s\n", marker, name)
+	}
+
+	if dir, err := soundsDir(); err == nil {
+		fmt.Println()
+		fmt.Printf("💡 Custom sound themes are loaded from %s\n", dir)
+	}
+}
+
+func runSoundsPreview(cmd *cobra.Command, args []string) {
+	name := selectedSoundThemeName(loadConfig())
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	// Recordings advance a simulated clock so the preview is written instantly
+	now := time.Now()
+	audio, err := newAudioManager(func() time.Time { return now })
+	if err != nil {
+		fmt.Printf("❌ Audio unavailable: %v\n", err)
+		os.Exit(1)
+	}
+	if err := applySoundSettings(audio, name, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("🔊 Sound theme: %s\n", name)
+	for _, event := range core.SoundEvents {
+		if !audio.HasSound(event) {
+			fmt.Printf("   %-17s (silent)\n", event)
+			continue
+		}
+		fmt.Printf("   %-17s %v\n", event, audio.SoundDuration(event).Round(time.Millisecond))
+		audio.Play(event)
+
+		wait := audio.SoundDuration(event) + 300*time.Millisecond
+		if audioOut == "" {
+			time.Sleep(wait)
+		}
+		now = now.Add(wait)
+	}
+
+	if err := audio.Close(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if audioOut != "" && audioOut != "null" {
+		fmt.Printf("💾 Saved to %s\n", audioOut)
+	}
+}
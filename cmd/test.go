@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var (
+	testTime        int
+	testLanguage    string
+	testLeaderboard bool
+	testDifficulty  string
+)
+
+var testCmd = &cobra.Command{
+	Use:   "test [file]",
+	Short: "Take a timed typing test",
+	Long: `Take a timed typing test. Random snippets of code are fed through the
+code pane until the time runs out. Speed is scored in standard words of
+five characters, so results compare fairly across languages, and each
+result is ranked on a personal leaderboard for its duration and
+difficulty.
+
+Examples:
+  syntaxrush test                        # 60 second Go test
+  syntaxrush test --time 30 --lang py    # 30 second Python test
+  syntaxrush test ./main.go -t 15        # Snippets from your own file
+  syntaxrush test --leaderboard          # Best results for every duration
+  syntaxrush test --leaderboard -d hard  # Best results at hard difficulty`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  runTest,
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+
+	testCmd.Flags().IntVarP(&testTime, "time", "t", 60, "Test length in seconds ("+testDurationList()+")")
+	testCmd.Flags().StringVarP(&testLanguage, "lang", "l", "go", "Language of the built-in samples (go, python, js, cpp)")
+	testCmd.Flags().BoolVar(&testLeaderboard, "leaderboard", false, "Show your best results instead of starting a test")
+	testCmd.Flags().StringVarP(&testDifficulty, "difficulty", "d", "", "How much of each line is typed: easy, normal or hard (default from config)")
+	testCmd.Flags().BoolVarP(&mute, "mute", "m", false, "Disable audio feedback")
+}
+
+func runTest(cmd *cobra.Command, args []string) {
+	limit, err := testDuration(testTime)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	level, err := selectedDifficulty(cmd, testDifficulty)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if testLeaderboard {
+		if !cmd.Flags().Changed("time") {
+			limit = 0
+		}
+		language := ""
+		if cmd.Flags().Changed("lang") {
+			language = core.LanguageFromName(testLanguage)
+		}
+		displayTestLeaderboard(limit, language, level)
+		return
+	}
+
+	model := ui.NewModel()
+
+	// Sound settings apply even when muted, so unmuting plays them
+	if err := configureAudio(model, nil, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	if mute {
+		model.SetAudioEnabled(false)
+	}
+
+	if err := configureDisplay(model); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := configureKeyboard(model, loadConfig()); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	// Tests are always scored in standard words so they compare across languages
+	model.SetWPMDefinition(core.WPMStandard)
+	model.SetDifficulty(level)
+
+	if history, err := core.OpenDefaultHistory(); err == nil {
+		model.SetHistory(history)
+	}
+
+	// A file argument takes priority over the language samples
+	source := testLanguage
+	if len(args) > 0 {
+		source = args[0]
+	}
+	if err := model.LoadFile(core.ExpandFilePath(source)); err != nil {
+		displayBanner()
+		fmt.Printf("❌ Error loading file '%s': %v\n", source, err)
+		os.Exit(1)
+	}
+	if err := model.SetTimedTest(limit, time.Now().UnixNano()); err != nil {
+		fmt.Printf("❌ Error preparing test: %v\n", err)
+		os.Exit(1)
+	}
+	model.StartPracticeDirectly()
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	finalModel, err := p.Run()
+	if err != nil {
+		fmt.Printf("Error running program: %v\n", err)
+		os.Exit(1)
+	}
+
+	result := finalModel.(*ui.Model)
+	if result.IsSessionComplete() {
+		displayTestResult(result)
+	}
+	result.Cleanup()
+}
+
+// testDuration converts a --time value to a supported test length
+func testDuration(seconds int) (time.Duration, error) {
+	limit := time.Duration(seconds) * time.Second
+	for _, d := range core.TestDurations {
+		if d == limit {
+			return limit, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported test length %ds (use %s)", seconds, testDurationList())
+}
+
+// testDurationList returns the supported test lengths in seconds, e.g. "15, 30, 60"
+func testDurationList() string {
+	names := make([]string, len(core.TestDurations))
+	for i, d := range core.TestDurations {
+		names[i] = fmt.Sprintf("%d", int(d.Seconds()))
+	}
+	return strings.Join(names, ", ")
+}
+
+// displayTestResult prints the score of a finished test
+func displayTestResult(model *ui.Model) {
+	session := model.Session()
+	stats := session.Stats()
+
+	fmt.Printf("\n⏱️  %ds Test Complete!\n", int(session.TimeLimit().Seconds()))
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("⚡ WPM: %.1f (%s: %s)\n", session.StandardWPM(), core.WPMStandard, core.WPMStandard.Description())
+	fmt.Printf("🚀 Raw WPM: %.1f │ Net WPM: %.1f │ KPM: %.0f\n", stats.RawWPM, stats.NetWPM, stats.KPM)
+	fmt.Printf("🎯 Accuracy: %.1f%%\n", stats.Accuracy)
+	fmt.Printf("❌ Mistakes: %d\n", stats.TotalMistakes)
+
+	switch rank, entries := model.TestRank(); {
+	case rank == 1 && entries > 1:
+		fmt.Println("🏆 New personal best!")
+	case rank > 0:
+		fmt.Printf("🏅 Leaderboard: #%d of %d\n", rank, entries)
+	}
+}
+
+// displayTestLeaderboard prints the best timed tests at a difficulty for
+// one length, or every length if limit is 0
+func displayTestLeaderboard(limit time.Duration, language string, difficulty core.Difficulty) {
+	history, err := core.OpenDefaultHistory()
+	if err != nil {
+		fmt.Printf("❌ Error opening history: %v\n", err)
+		os.Exit(1)
+	}
+	records, err := history.Load()
+	if err != nil {
+		fmt.Printf("❌ Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("🏆 SyntaxRush Test Leaderboard")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("🎚️  Difficulty: %s\n", difficulty)
+
+	shown := 0
+	for _, d := range core.TestDurations {
+		if limit != 0 && d != limit {
+			continue
+		}
+		board := core.TestLeaderboard(records, d, language, difficulty)
+		if len(board) == 0 {
+			continue
+		}
+		if len(board) > 10 {
+			board = board[:10]
+		}
+
+		fmt.Printf("\n⏱️  %ds:\n", int(d.Seconds()))
+		for i, record := range board {
+			fmt.Printf("   %2d. %6.1f WPM  %5.1f%%  %-10s %s\n",
+				i+1, record.WPM, record.Accuracy, record.Language, record.Timestamp.Format("2006-01-02"))
+		}
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Println("\n📈 No timed tests recorded yet.")
+		fmt.Println("💡 Take one with: syntaxrush test --time 60")
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/theme"
+	"github.com/vamshi1188/SyntaxRush/ui"
+)
+
+// Flag variables
+var (
+	themeName  string
+	errorStyle string
+	noColor    bool
+)
+
+var themeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "List and preview color themes",
+	Long: `List and preview the available color themes.
+
+Built-in themes are "dark", "light", their colorblind-safe variants
+"dark-colorblind" and "light-colorblind", and "mono", which uses only bold,
+underline and inverse video. "mono" is selected automatically when the
+NO_COLOR environment variable is set or --no-color is given.
+
+Custom themes are JSON files (*.json; TOML is not supported) in the themes
+folder of your SyntaxRush config directory. A theme file names a base
+theme and overrides individual styles:
+
+  {
+    "name": "ocean",
+    "base": "dark",
+    "styles": {
+      "correct_char":   {"foreground": "#7FDBFF"},
+      "incorrect_char": {"foreground": "#FF851B", "underline": true},
+      "power_zen":      {"foreground": "#B10DC9"}
+    }
+  }
+
+Select a theme with --theme or: syntaxrush config set theme <name>
+
+Mistyped characters can additionally be marked by underline, inverse video,
+by showing the typed character next to the expected one ("typed"), or in a
+row under the line ("below"):
+
+  syntaxrush practice --error-style typed
+  syntaxrush config set error_style inverse`,
+}
+
+var themeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available themes",
+	Args:  cobra.NoArgs,
+	Run:   runThemeList,
+}
+
+var themePreviewCmd = &cobra.Command{
+	Use:   "preview [name]",
+	Short: "Show a sample of the UI in a theme",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   runThemePreview,
+}
+
+func init() {
+	rootCmd.AddCommand(themeCmd)
+	themeCmd.AddCommand(themeListCmd)
+	themeCmd.AddCommand(themePreviewCmd)
+
+	rootCmd.PersistentFlags().StringVar(&themeName, "theme", "", "Color theme (see 'syntaxrush theme list')")
+	rootCmd.PersistentFlags().StringVar(&errorStyle, "error-style", "", "Mistake marker: "+strings.Join(core.ErrorStyles, ", "))
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colors (same as setting NO_COLOR)")
+}
+
+// colorDisabled reports whether the user asked for output without colors
+func colorDisabled() bool {
+	return noColor || os.Getenv("NO_COLOR") != ""
+}
+
+// isTerminal reports whether stdout is a terminal
+func isTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// themesDir returns the folder custom theme files are loaded from
+func themesDir() (string, error) {
+	dir, err := core.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// loadUserThemes registers custom themes, warning about files that fail to load
+func loadUserThemes() {
+	dir, err := themesDir()
+	if err != nil {
+		return
+	}
+	if _, err := theme.LoadDir(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Some themes could not be loaded: %v\n", err)
+	}
+}
+
+// loadConfig reads user settings, falling back to defaults with a warning
+func loadConfig() *core.Config {
+	config, err := core.LoadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Using default settings: %v\n", err)
+		return core.DefaultConfig()
+	}
+	return config
+}
+
+// selectedThemeName returns the theme chosen by flag, then config, then default
+func selectedThemeName() string {
+	if themeName != "" {
+		return themeName
+	}
+	if colorDisabled() {
+		return theme.MonochromeName
+	}
+	if name := loadConfig().Theme; name != "" {
+		return name
+	}
+	return theme.DefaultName
+}
+
+// resolveTheme loads custom themes and returns the selected theme
+func resolveTheme() (*theme.Theme, error) {
+	loadUserThemes()
+	name := selectedThemeName()
+	applyColorProfile(name)
+	return theme.Get(name)
+}
+
+// applyColorProfile makes sure the monochrome theme still renders its text
+// attributes. With NO_COLOR set, lipgloss drops all styling, including bold
+// and inverse video, so the profile is raised to plain ANSI, which the mono
+// theme only uses for attributes.
+func applyColorProfile(name string) {
+	if name == theme.MonochromeName && lipgloss.ColorProfile() == termenv.Ascii && isTerminal() {
+		lipgloss.SetColorProfile(termenv.ANSI)
+	}
+}
+
+// selectedErrorStyle returns the mistake marker chosen by flag, then config
+func selectedErrorStyle() (ui.ErrorStyle, error) {
+	style := errorStyle
+	if style == "" {
+		style = loadConfig().ErrorStyle
+	}
+	if style == "" {
+		return ui.ErrorStyleColor, nil
+	}
+	if err := core.DefaultConfig().Set("error_style", style); err != nil {
+		return "", err
+	}
+	return ui.ErrorStyle(style), nil
+}
+
+// configureDisplay applies the selected theme, error style and key
+// bindings to a model
+func configureDisplay(model *ui.Model) error {
+	t, err := resolveTheme()
+	if err != nil {
+		return err
+	}
+	model.SetTheme(t)
+
+	style, err := selectedErrorStyle()
+	if err != nil {
+		return err
+	}
+	model.SetErrorStyle(style)
+	model.SetKeyBindings(loadConfig().KeyBindings())
+	return nil
+}
+
+func runThemeList(cmd *cobra.Command, args []string) {
+	loadUserThemes()
+	current := selectedThemeName()
+
+	fmt.Println("🎨 Available Themes")
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━")
+	for _, name := range theme.Names() {
+		marker := "  "
+		if name == current {
+			marker = "▶ "
+		}
+		fmt.Printf("%s%s\n", marker, name)
+	}
+
+	if dir, err := themesDir(); err == nil {
+		fmt.Println()
+		fmt.Printf("💡 Custom themes are loaded from %s\n", dir)
+	}
+}
+
+func runThemePreview(cmd *cobra.Command, args []string) {
+	loadUserThemes()
+
+	name := selectedThemeName()
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	t, err := theme.Get(name)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	applyColorProfile(name)
+
+	fmt.Println(ui.RenderThemePreview(name, t))
+}
@@ -1,30 +1,47 @@
 package core
 
 import (
-	"io"
-	"math"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/hajimehoshi/oto/v2"
 )
 
+// Playback format shared by every sound and output
+const (
+	audioSampleRate = 44100
+	audioChannels   = 2
+	audioBitDepth   = 2 // 16-bit
+)
+
+// AudioOutput plays interleaved 16-bit stereo samples
+type AudioOutput interface {
+	Play(samples []int16)
+	Close() error
+}
+
+// pausableOutput is an output that can release the sound device while
+// audio is disabled
+type pausableOutput interface {
+	Pause()
+	Resume()
+}
+
 // AudioManager handles sound effects for the application
 type AudioManager struct {
-	context    *oto.Context
-	sampleRate int
-	channels   int
-	bitDepth   int
+	output  AudioOutput
+	enabled bool
+	theme   *SoundTheme
+	sounds  map[SoundEvent][]int16 // Rendered sounds at the theme's volume
+	volume  float64
+	volumes map[SoundEvent]float64
 }
 
-// NewAudioManager creates a new audio manager
+// NewAudioManager creates a new audio manager that plays through the sound card
 func NewAudioManager() (*AudioManager, error) {
-	// Audio configuration
-	sampleRate := 44100
-	channels := 2
-	bitDepth := 2 // 16-bit
-
 	// Initialize oto context with v2 API
-	ctx, ready, err := oto.NewContext(sampleRate, channels, oto.FormatSignedInt16LE)
+	ctx, ready, err := oto.NewContext(audioSampleRate, audioChannels, oto.FormatSignedInt16LE)
 	if err != nil {
 		return nil, err
 	}
@@ -32,122 +49,234 @@ func NewAudioManager() (*AudioManager, error) {
 	// Wait for the audio context to be ready
 	<-ready
 
-	return &AudioManager{
-		context:    ctx,
-		sampleRate: sampleRate,
-		channels:   channels,
-		bitDepth:   bitDepth,
-	}, nil
-}
-
-// generateBeepWave generates a beep sound wave
-func (am *AudioManager) generateBeepWave(frequency float64, duration time.Duration) []byte {
-	samples := int(float64(am.sampleRate) * duration.Seconds())
-	data := make([]byte, samples*am.channels*am.bitDepth)
-
-	for i := 0; i < samples; i++ {
-		// Generate sine wave
-		t := float64(i) / float64(am.sampleRate)
-		sample := math.Sin(2 * math.Pi * frequency * t)
-
-		// Apply envelope to avoid clicks (fade in/out)
-		envelope := 1.0
-		fadeTime := 0.01 // 10ms fade
-		fadeSamples := int(fadeTime * float64(am.sampleRate))
-
-		if i < fadeSamples {
-			envelope = float64(i) / float64(fadeSamples)
-		} else if i > samples-fadeSamples {
-			envelope = float64(samples-i) / float64(fadeSamples)
-		}
+	return NewAudioManagerWithOutput(newOtoOutput(ctx)), nil
+}
 
-		sample *= envelope * 0.3 // Reduce volume to 30%
+// NewAudioManagerWithOutput creates an audio manager that plays through
+// output, using the default sound theme
+func NewAudioManagerWithOutput(output AudioOutput) *AudioManager {
+	am := &AudioManager{
+		output:  output,
+		enabled: true,
+		volume:  1,
+		volumes: make(map[SoundEvent]float64),
+	}
 
-		// Convert to 16-bit PCM
-		pcm := int16(sample * 32767)
+	// Built-in themes are synthesized and cannot fail to render
+	t, _ := GetSoundTheme(DefaultSoundTheme)
+	am.SetSoundTheme(t)
+	return am
+}
 
-		// Write stereo samples
-		for ch := 0; ch < am.channels; ch++ {
-			offset := (i*am.channels + ch) * am.bitDepth
-			data[offset] = byte(pcm & 0xff)
-			data[offset+1] = byte((pcm >> 8) & 0xff)
+// SetSoundTheme renders and switches to a sound theme. The current theme
+// is kept if any of the new sounds fail to load.
+func (am *AudioManager) SetSoundTheme(t *SoundTheme) error {
+	sounds := make(map[SoundEvent][]int16)
+	for _, event := range SoundEvents {
+		samples, err := t.Render(event)
+		if err != nil {
+			return err
+		}
+		if len(samples) > 0 {
+			sounds[event] = samples
 		}
 	}
 
-	return data
+	am.theme = t
+	am.sounds = sounds
+	return nil
+}
+
+// SoundTheme returns the active sound theme
+func (am *AudioManager) SoundTheme() *SoundTheme {
+	return am.theme
+}
+
+// SetVolume sets the master volume from 0 (silent) to 1
+func (am *AudioManager) SetVolume(volume float64) {
+	am.volume = volume
+}
+
+// SetEventVolume sets the volume of one event from 0 (silent) to 1
+func (am *AudioManager) SetEventVolume(event SoundEvent, volume float64) {
+	am.volumes[event] = volume
+}
+
+// eventVolume returns the combined master and event volume
+func (am *AudioManager) eventVolume(event SoundEvent) float64 {
+	volume, ok := am.volumes[event]
+	if !ok {
+		volume = 1
+	}
+	return am.volume * volume
+}
+
+// HasSound reports whether an event plays anything at the current settings
+func (am *AudioManager) HasSound(event SoundEvent) bool {
+	return len(am.sounds[event]) > 0 && am.eventVolume(event) > 0
 }
 
-// PlayErrorBeep plays a beep sound for typing mistakes
-func (am *AudioManager) PlayErrorBeep() {
-	if am.context == nil {
+// SetEnabled turns sound effects on or off without releasing the output,
+// so audio can be re-enabled at any time
+func (am *AudioManager) SetEnabled(enabled bool) {
+	if enabled == am.enabled {
 		return
 	}
+	am.enabled = enabled
 
-	// Generate a short beep (800Hz for 100ms)
-	beepData := am.generateBeepWave(800, 100*time.Millisecond)
+	if output, ok := am.output.(pausableOutput); ok {
+		if enabled {
+			output.Resume()
+		} else {
+			output.Pause()
+		}
+	}
+}
 
-	// Create a player and play the sound
-	go func() {
-		player := am.context.NewPlayer(io.NopCloser(&beepReader{data: beepData}))
-		defer player.Close()
-		player.Play()
+// Enabled reports whether sound effects are on
+func (am *AudioManager) Enabled() bool {
+	return am.enabled
+}
 
-		// Wait for the sound to finish
-		time.Sleep(100 * time.Millisecond)
-	}()
+// SoundDuration returns how long the sound for an event plays
+func (am *AudioManager) SoundDuration(event SoundEvent) time.Duration {
+	return samplesDuration(len(am.sounds[event]))
 }
 
-// PlaySuccessSound plays a pleasant sound for line completion
-func (am *AudioManager) PlaySuccessSound() {
-	if am.context == nil {
+// Play plays the sound for an event, if the theme defines one
+func (am *AudioManager) Play(event SoundEvent) {
+	if am.output == nil || !am.enabled || !am.HasSound(event) {
 		return
 	}
 
-	// Generate a pleasant two-tone sound
-	go func() {
-		// First tone (C note - 523Hz)
-		tone1 := am.generateBeepWave(523, 80*time.Millisecond)
+	samples := am.sounds[event]
+	if volume := am.eventVolume(event); volume != 1 {
+		samples = scaleSamples(samples, volume)
+	}
+	am.output.Play(samples)
+}
 
-		// Second tone (E note - 659Hz)
-		tone2 := am.generateBeepWave(659, 80*time.Millisecond)
+// Close closes the audio output
+func (am *AudioManager) Close() error {
+	if am.output == nil {
+		return nil
+	}
+	return am.output.Close()
+}
 
-		// Combine tones with a small gap
-		combined := make([]byte, len(tone1)+len(tone2))
-		copy(combined[:len(tone1)], tone1)
-		copy(combined[len(tone1):], tone2)
+// samplesDuration returns how long interleaved samples take to play
+func samplesDuration(samples int) time.Duration {
+	frames := samples / audioChannels
+	return time.Duration(frames) * time.Second / audioSampleRate
+}
 
-		player := am.context.NewPlayer(io.NopCloser(&beepReader{data: combined}))
-		defer player.Close()
-		player.Play()
+// mixerBufferSize is the player buffer in bytes; 20ms keeps key sounds in
+// time with the keystrokes
+const mixerBufferSize = audioSampleRate / 50 * audioChannels * audioBitDepth
 
-		// Wait for the sound to finish
-		time.Sleep(200 * time.Millisecond)
-	}()
+// otoOutput plays sounds through the system sound card. A single player
+// streams from the mixer for the lifetime of the output.
+type otoOutput struct {
+	context *oto.Context
+	mixer   *Mixer
+	player  oto.Player
 }
 
-// Close closes the audio context
-func (am *AudioManager) Close() {
-	if am.context != nil {
-		am.context.Suspend()
+// newOtoOutput starts the long-lived player for a context
+func newOtoOutput(context *oto.Context) *otoOutput {
+	mixer := NewMixer(defaultMaxVoices)
+	player := context.NewPlayer(mixer)
+	if sizer, ok := player.(oto.BufferSizeSetter); ok {
+		sizer.SetBufferSize(mixerBufferSize)
 	}
+	player.Play()
+
+	return &otoOutput{context: context, mixer: mixer, player: player}
+}
+
+// Play adds the sound to the mix
+func (o *otoOutput) Play(samples []int16) {
+	o.mixer.Add(samples)
+}
+
+// Pause stops the player and drops pending sounds
+func (o *otoOutput) Pause() {
+	o.player.Pause()
+	o.mixer.Clear()
 }
 
-// beepReader implements io.Reader for audio data
-type beepReader struct {
-	data   []byte
-	offset int
+// Resume restarts the player
+func (o *otoOutput) Resume() {
+	o.player.Play()
 }
 
-func (br *beepReader) Read(p []byte) (n int, err error) {
-	if br.offset >= len(br.data) {
-		return 0, io.EOF
+// Close stops the player and suspends the audio context
+func (o *otoOutput) Close() error {
+	if err := o.player.Close(); err != nil {
+		return err
 	}
+	return o.context.Suspend()
+}
+
+// NullOutput discards sounds, counting how many were played
+type NullOutput struct {
+	Plays int
+}
 
-	n = copy(p, br.data[br.offset:])
-	br.offset += n
-	return n, nil
+// Play counts the sound without playing it
+func (o *NullOutput) Play(samples []int16) {
+	o.Plays++
 }
 
-func (br *beepReader) Close() error {
+// Close does nothing
+func (o *NullOutput) Close() error {
+	return nil
+}
+
+// WAVOutput records sounds into a WAV file instead of playing them. Each
+// sound is placed at the time it was played, so the file can be listened
+// to as a recording of the session.
+type WAVOutput struct {
+	path    string
+	clock   Clock
+	start   time.Time
+	samples []int16
+}
+
+// NewWAVOutput creates an output that writes to path when closed. Sound
+// positions are taken from clock, or the wall clock if clock is nil.
+func NewWAVOutput(path string, clock Clock) *WAVOutput {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &WAVOutput{path: path, clock: clock, start: clock()}
+}
+
+// Play mixes the sound into the recording at the current time
+func (o *WAVOutput) Play(samples []int16) {
+	offset := int(o.clock().Sub(o.start).Seconds()*audioSampleRate) * audioChannels
+	if offset < 0 {
+		offset = 0
+	}
+
+	if end := offset + len(samples); end > len(o.samples) {
+		o.samples = append(o.samples, make([]int16, end-len(o.samples))...)
+	}
+	for i, sample := range samples {
+		o.samples[offset+i] = clampSample(float64(o.samples[offset+i]) + float64(sample))
+	}
+}
+
+// Close writes the recording to disk
+func (o *WAVOutput) Close() error {
+	file, err := os.Create(o.path)
+	if err != nil {
+		return fmt.Errorf("error creating sound file: %v", err)
+	}
+	defer file.Close()
+
+	if err := writeWAV(file, o.samples, audioSampleRate, audioChannels); err != nil {
+		return fmt.Errorf("error writing sound file: %v", err)
+	}
 	return nil
 }
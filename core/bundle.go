@@ -0,0 +1,254 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BundleVersion is the format of result bundles written by this version
+const BundleVersion = 1
+
+// BundleExtension ends the names of result bundle files
+const BundleExtension = ".syntaxrush.json"
+
+// ResultBundle is one user's results exported from their history, for
+// merging into a team leaderboard. The checksum catches bundles that were
+// edited or damaged; the signature, made with a key the team shares,
+// catches bundles written by anyone without the key.
+type ResultBundle struct {
+	Version   int            `json:"version"`
+	User      string         `json:"user"`
+	Exported  time.Time      `json:"exported"`
+	Results   []BundleResult `json:"results"`
+	Checksum  string         `json:"checksum"`            // SHA-256 of the bundle without checksum and signature
+	Signature string         `json:"signature,omitempty"` // HMAC-SHA256 with the team key, if one was used
+
+	path string
+}
+
+// BundleResult is one session in a result bundle. WPM is always in
+// standard five-character words so results compare across users.
+type BundleResult struct {
+	Timestamp   time.Time  `json:"timestamp"`
+	Snippet     string     `json:"snippet"`                // Base name of the practiced file
+	SnippetHash string     `json:"snippet_hash,omitempty"` // ContentHash of the practiced lines
+	Language    string     `json:"language"`
+	Mode        string     `json:"mode,omitempty"`
+	TimeLimitS  int        `json:"time_limit_s,omitempty"`
+	Difficulty  Difficulty `json:"difficulty,omitempty"` // Missing means normal
+	DurationMS  int64      `json:"duration_ms"`
+	WPM         float64    `json:"wpm"`
+	Accuracy    float64    `json:"accuracy"`
+	Mistakes    int        `json:"mistakes"`
+	Characters  int        `json:"characters"`
+	Keystrokes  *KeyLog    `json:"keystrokes,omitempty"` // Lets the leaderboard replay and validate the result
+}
+
+// TimeLimit returns the length of a timed test, or 0 for practice sessions
+func (r BundleResult) TimeLimit() time.Duration {
+	return time.Duration(r.TimeLimitS) * time.Second
+}
+
+// Level returns the difficulty the session was typed at
+func (r BundleResult) Level() Difficulty {
+	if r.Difficulty == "" {
+		return DifficultyNormal
+	}
+	return r.Difficulty
+}
+
+// NewResultBundle collects a user's results from their session records
+func NewResultBundle(user string, records []SessionRecord, exported time.Time) *ResultBundle {
+	bundle := &ResultBundle{Version: BundleVersion, User: user, Exported: exported}
+	for _, record := range records {
+		if record.File == "" {
+			continue
+		}
+		wpm, _ := record.WPMAs(WPMStandard)
+		bundle.Results = append(bundle.Results, BundleResult{
+			Timestamp:   record.Timestamp,
+			Snippet:     filepath.Base(record.File),
+			SnippetHash: record.ContentHash,
+			Language:    record.Language,
+			Mode:        record.Mode,
+			TimeLimitS:  record.TimeLimitS,
+			Difficulty:  record.Difficulty,
+			DurationMS:  record.DurationMS,
+			WPM:         wpm,
+			Accuracy:    record.Accuracy,
+			Mistakes:    record.Mistakes,
+			Characters:  record.Characters,
+			Keystrokes:  record.Keystrokes,
+		})
+	}
+	return bundle
+}
+
+// content returns the bytes the checksum and signature cover
+func (b *ResultBundle) content() ([]byte, error) {
+	unsealed := *b
+	unsealed.Checksum = ""
+	unsealed.Signature = ""
+	return json.Marshal(unsealed)
+}
+
+// Seal sets the bundle's checksum, and its signature if key is not empty
+func (b *ResultBundle) Seal(key string) error {
+	content, err := b.content()
+	if err != nil {
+		return fmt.Errorf("error encoding bundle: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	b.Checksum = hex.EncodeToString(sum[:])
+	b.Signature = ""
+	if key != "" {
+		b.Signature = sign(content, key)
+	}
+	return nil
+}
+
+// Verify checks the bundle's checksum and, if key is not empty, that it
+// was signed with key
+func (b *ResultBundle) Verify(key string) error {
+	content, err := b.content()
+	if err != nil {
+		return fmt.Errorf("error encoding bundle: %v", err)
+	}
+
+	sum := sha256.Sum256(content)
+	if !hmac.Equal([]byte(b.Checksum), []byte(hex.EncodeToString(sum[:]))) {
+		return fmt.Errorf("checksum does not match; the bundle was changed after it was exported")
+	}
+	if key == "" {
+		return nil
+	}
+	if b.Signature == "" {
+		return fmt.Errorf("bundle is not signed")
+	}
+	if !hmac.Equal([]byte(b.Signature), []byte(sign(content, key))) {
+		return fmt.Errorf("signature does not match the team key")
+	}
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 of content with key
+func sign(content []byte, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(content)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Path returns the file the bundle was loaded from
+func (b *ResultBundle) Path() string {
+	return b.path
+}
+
+// WriteFile saves the bundle to path
+func (b *ResultBundle) WriteFile(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding bundle: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing bundle: %v", err)
+	}
+	return nil
+}
+
+// LoadResultBundle reads a bundle without verifying it
+func LoadResultBundle(path string) (*ResultBundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle: %v", err)
+	}
+
+	bundle := &ResultBundle{path: path}
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("error parsing bundle %s: %v", path, err)
+	}
+	if bundle.Version != BundleVersion {
+		return nil, fmt.Errorf("bundle %s has unsupported version %d", path, bundle.Version)
+	}
+	if bundle.User == "" {
+		return nil, fmt.Errorf("bundle %s has no user", path)
+	}
+	return bundle, nil
+}
+
+// RejectedResult is a result left out of a leaderboard because it failed
+// validation
+type RejectedResult struct {
+	User   string
+	Result BundleResult
+	Err    error
+}
+
+// ValidateResults removes the results that fail validation from every
+// bundle and returns them. Results recorded without the keystrokes to
+// verify them are kept only when allowUnverified is set.
+func ValidateResults(bundles []*ResultBundle, allowUnverified bool) []RejectedResult {
+	var rejected []RejectedResult
+	for _, bundle := range bundles {
+		var valid []BundleResult
+		for _, result := range bundle.Results {
+			err := result.Validate()
+			if err == nil || (err == ErrUnverifiable && allowUnverified) {
+				valid = append(valid, result)
+				continue
+			}
+			rejected = append(rejected, RejectedResult{User: bundle.User, Result: result, Err: err})
+		}
+		bundle.Results = valid
+	}
+	return rejected
+}
+
+// LoadResultBundles reads and verifies every bundle in dir and its
+// subfolders. Bundles that fail to load or verify are skipped and reported
+// together.
+func LoadResultBundles(dir, key string) ([]*ResultBundle, error) {
+	var bundles []*ResultBundle
+	var problems []string
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && path != dir {
+			return filepath.SkipDir // .git and the like
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), BundleExtension) {
+			return nil
+		}
+
+		bundle, err := LoadResultBundle(path)
+		if err == nil {
+			if err = bundle.Verify(key); err != nil {
+				err = fmt.Errorf("%s: %v", path, err)
+			}
+		}
+		if err != nil {
+			problems = append(problems, err.Error())
+			return nil
+		}
+		bundles = append(bundles, bundle)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading results folder: %v", err)
+	}
+
+	if len(problems) > 0 {
+		return bundles, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return bundles, nil
+}
@@ -0,0 +1,275 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config holds persistent user settings
+type Config struct {
+	Theme         string            `json:"theme"`
+	ErrorStyle    string            `json:"error_style"`
+	SoundTheme    string            `json:"sound_theme"`
+	Volume        int               `json:"volume"`                  // Master volume in percent
+	EventVolumes  map[string]int    `json:"event_volumes,omitempty"` // Per-event volume in percent
+	PaceWPM       int               `json:"pace_wpm"`                // Metronome target, 0 when off
+	WPMDefinition string            `json:"wpm_definition"`          // How WPM counts words
+	Layout        string            `json:"keyboard_layout"`         // Keyboard layout for the finger report
+	ShowKeyboard  bool              `json:"show_keyboard"`           // On-screen keyboard while typing
+	Difficulty    string            `json:"difficulty"`              // How much of each line is typed
+	Keys          map[string]string `json:"keys,omitempty"`          // Key bound to each action, "" when unbound
+
+	path string
+}
+
+// ErrorStyles are the ways mistyped characters can be marked
+var ErrorStyles = []string{"color", "underline", "inverse", "typed", "below"}
+
+// configSetters validates and applies each settable config key
+var configSetters = map[string]func(c *Config, value string) error{
+	"theme": func(c *Config, value string) error {
+		c.Theme = value
+		return nil
+	},
+	"error_style": func(c *Config, value string) error {
+		if err := checkChoice("error_style", value, ErrorStyles); err != nil {
+			return err
+		}
+		c.ErrorStyle = value
+		return nil
+	},
+	"sound_theme": func(c *Config, value string) error {
+		c.SoundTheme = value
+		return nil
+	},
+	"volume": func(c *Config, value string) error {
+		volume, err := parseVolume("volume", value)
+		if err != nil {
+			return err
+		}
+		c.Volume = volume
+		return nil
+	},
+	"pace_wpm": func(c *Config, value string) error {
+		wpm, err := strconv.Atoi(value)
+		if err != nil || wpm < 0 || wpm > 300 {
+			return fmt.Errorf("invalid pace_wpm %q (use 1 to 300, or 0 to turn pacing off)", value)
+		}
+		c.PaceWPM = wpm
+		return nil
+	},
+	"wpm_definition": func(c *Config, value string) error {
+		if err := checkChoice("wpm_definition", value, WPMDefinitionNames()); err != nil {
+			return err
+		}
+		c.WPMDefinition = value
+		return nil
+	},
+	"keyboard_layout": func(c *Config, value string) error {
+		c.Layout = value
+		return nil
+	},
+	"show_keyboard": func(c *Config, value string) error {
+		show, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid show_keyboard %q (use true or false)", value)
+		}
+		c.ShowKeyboard = show
+		return nil
+	},
+	"difficulty": func(c *Config, value string) error {
+		if err := checkChoice("difficulty", value, DifficultyNames()); err != nil {
+			return err
+		}
+		c.Difficulty = value
+		return nil
+	},
+}
+
+// configGetters reads each config key as a string
+var configGetters = map[string]func(c *Config) string{
+	"theme":           func(c *Config) string { return c.Theme },
+	"error_style":     func(c *Config) string { return c.ErrorStyle },
+	"sound_theme":     func(c *Config) string { return c.SoundTheme },
+	"volume":          func(c *Config) string { return strconv.Itoa(c.Volume) },
+	"pace_wpm":        func(c *Config) string { return strconv.Itoa(c.PaceWPM) },
+	"wpm_definition":  func(c *Config) string { return c.WPMDefinition },
+	"keyboard_layout": func(c *Config) string { return c.Layout },
+	"show_keyboard":   func(c *Config) string { return strconv.FormatBool(c.ShowKeyboard) },
+	"difficulty":      func(c *Config) string { return c.Difficulty },
+}
+
+// Each sound event has its own volume key, e.g. "volume.keypress"
+func init() {
+	for _, event := range SoundEvents {
+		event := event
+		key := "volume." + string(event)
+		configSetters[key] = func(c *Config, value string) error {
+			volume, err := parseVolume(key, value)
+			if err != nil {
+				return err
+			}
+			if c.EventVolumes == nil {
+				c.EventVolumes = make(map[string]int)
+			}
+			c.EventVolumes[string(event)] = volume
+			return nil
+		}
+		configGetters[key] = func(c *Config) string {
+			return strconv.Itoa(c.EventVolume(event))
+		}
+	}
+
+	// Each action has its own key binding, e.g. "keys.palette"
+	for _, action := range KeyActions {
+		action := action
+		key := "keys." + string(action)
+		configSetters[key] = func(c *Config, value string) error {
+			return c.setKey(action, value)
+		}
+		configGetters[key] = func(c *Config) string {
+			if binding := c.Key(action); binding != "" {
+				return binding
+			}
+			return "none"
+		}
+	}
+}
+
+// parseVolume reads a volume percentage between 0 and 100
+func parseVolume(key, value string) (int, error) {
+	volume, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || volume < 0 || volume > 100 {
+		return 0, fmt.Errorf("invalid %s %q (use a percentage from 0 to 100)", key, value)
+	}
+	return volume, nil
+}
+
+// EventVolume returns the volume percentage for a sound event
+func (c *Config) EventVolume(event SoundEvent) int {
+	if volume, ok := c.EventVolumes[string(event)]; ok {
+		return volume
+	}
+	return 100
+}
+
+// checkChoice returns an error if value is not one of choices
+func checkChoice(key, value string, choices []string) error {
+	for _, choice := range choices {
+		if value == choice {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid %s %q (choose from: %s)", key, value, strings.Join(choices, ", "))
+}
+
+// DefaultConfig returns the settings used when no config file exists
+func DefaultConfig() *Config {
+	return &Config{
+		Theme:         "dark",
+		ErrorStyle:    "color",
+		SoundTheme:    DefaultSoundTheme,
+		Volume:        100,
+		WPMDefinition: string(DefaultWPMDefinition),
+		Layout:        DefaultKeyboardLayout,
+		Difficulty:    string(DifficultyNormal),
+	}
+}
+
+// ConfigDir returns the SyntaxRush directory inside the user's config directory
+func ConfigDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating config directory: %v", err)
+	}
+	return filepath.Join(configDir, "syntaxrush"), nil
+}
+
+// LoadConfig reads the config file, falling back to defaults if it is missing
+func LoadConfig() (*Config, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfigFile(filepath.Join(dir, "config.json"))
+}
+
+// LoadConfigFile reads settings from path. Keys missing from the file keep
+// their default values.
+func LoadConfigFile(path string) (*Config, error) {
+	config := DefaultConfig()
+	config.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading config: %v", err)
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+
+	// A key bound to several actions would run any one of them
+	if err := config.checkKeys(); err != nil {
+		return nil, fmt.Errorf("error in config %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// Path returns the file the config is loaded from and saved to
+func (c *Config) Path() string {
+	return c.path
+}
+
+// Save writes the config back to its file
+func (c *Config) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding config: %v", err)
+	}
+
+	if err := os.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing config: %v", err)
+	}
+	return nil
+}
+
+// Set changes a setting by key
+func (c *Config) Set(key, value string) error {
+	setter, ok := configSetters[key]
+	if !ok {
+		return fmt.Errorf("unknown config key: %s", key)
+	}
+	return setter(c, value)
+}
+
+// Get returns a setting by key
+func (c *Config) Get(key string) (string, error) {
+	getter, ok := configGetters[key]
+	if !ok {
+		return "", fmt.Errorf("unknown config key: %s", key)
+	}
+	return getter(c), nil
+}
+
+// ConfigKeys returns all settable config keys in alphabetical order
+func ConfigKeys() []string {
+	keys := make([]string, 0, len(configGetters))
+	for key := range configGetters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
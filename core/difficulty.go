@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Difficulty selects how much of each line is typed
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"   // Blank and comment-only lines are skipped for you
+	DifficultyNormal Difficulty = "normal" // Leading indentation is skipped for you
+	DifficultyHard   Difficulty = "hard"   // Every character is typed, indentation included
+)
+
+// Difficulties lists every difficulty, easiest first
+var Difficulties = []Difficulty{DifficultyEasy, DifficultyNormal, DifficultyHard}
+
+// ParseDifficulty resolves a difficulty name. An empty name is normal.
+func ParseDifficulty(name string) (Difficulty, error) {
+	if name == "" {
+		return DifficultyNormal, nil
+	}
+	for _, d := range Difficulties {
+		if string(d) == strings.ToLower(name) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown difficulty: %s (available: %s)", name, strings.Join(DifficultyNames(), ", "))
+}
+
+// DifficultyNames returns the names of every difficulty
+func DifficultyNames() []string {
+	names := make([]string, len(Difficulties))
+	for i, d := range Difficulties {
+		names[i] = string(d)
+	}
+	return names
+}
+
+// Description explains what is typed at the difficulty
+func (d Difficulty) Description() string {
+	switch d {
+	case DifficultyEasy:
+		return "indentation, blank lines and comment lines are skipped"
+	case DifficultyHard:
+		return "indentation is typed too"
+	default:
+		return "indentation is skipped"
+	}
+}
+
+// TypedText returns the part of a line that is typed
+func (d Difficulty) TypedText(line string) string {
+	if d == DifficultyHard {
+		return line
+	}
+	return strings.TrimLeft(line, " \t")
+}
+
+// Skips reports whether a line of code in language is skipped without
+// being typed
+func (d Difficulty) Skips(line, language string) bool {
+	if d != DifficultyEasy {
+		return false
+	}
+
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return true
+	}
+
+	// "#" starts preprocessor lines in C-family code, not comments
+	marker := "//"
+	if m, ok := lineComments[language]; ok {
+		marker = m
+	}
+	if strings.HasPrefix(trimmed, marker) {
+		return true
+	}
+	// Block comments, with the "* " that continues them on each line
+	return marker == "//" && (strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*/") ||
+		strings.HasPrefix(trimmed, "* ") || trimmed == "*")
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// symbolPatterns find the line declaring a symbol, most specific first.
+// NAME is replaced by the quoted symbol name.
+var symbolPatterns = []string{
+	`^\s*func\s+(\([^)]*\)\s*)?NAME\s*[\[(]`,                                        // Go functions and methods
+	`\b(type|class|struct|enum|trait|interface|impl)\s+NAME\b`,                      // Types and classes
+	`\b(def|fn|function\*?)\s+NAME\s*[<(\[]`,                                        // Python, Rust and JavaScript functions
+	`^\s*(export\s+)?(const|let|var)\s+NAME\s*(:[^=]*)?=`,                           // JavaScript functions bound to names
+	`^\s*(async\s+|static\s+|public\s+|private\s+|protected\s+)*NAME\s*\(.*\)\s*\{`, // JavaScript and Java methods
+	`^\s*[\w<>\[\]:*&,~ ]*[\s*&:]NAME\s*\([^;]*$`,                                   // C-family functions
+}
+
+// controlKeywords start lines that call a function rather than declare one
+var controlKeywords = regexp.MustCompile(`^\s*(if|for|while|switch|return|else|case|do)\b`)
+
+// ExtractSymbol returns the declaration of a function, method, type or
+// class by name, with the comments directly above it. Brace blocks end
+// where their braces balance; Python blocks end where the indentation does.
+func ExtractSymbol(lines []string, language, name string) ([]string, error) {
+	start, err := FindSymbol(lines, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var end int
+	if language == "Python" {
+		end = indentBlockEnd(lines, start)
+	} else {
+		end = braceBlockEnd(lines, start)
+	}
+
+	// Keep the doc comment and decorators above the declaration
+	for start > 0 && isCommentLine(lines[start-1]) {
+		start--
+	}
+	return dedent(lines[start : end+1]), nil
+}
+
+// FindSymbol returns the index of the line declaring a function, method,
+// type or class by name
+func FindSymbol(lines []string, name string) (int, error) {
+	for _, pattern := range symbolPatterns {
+		re := regexp.MustCompile(strings.ReplaceAll(pattern, "NAME", regexp.QuoteMeta(name)))
+		for i, line := range lines {
+			if re.MatchString(line) && !controlKeywords.MatchString(line) {
+				return i, nil
+			}
+		}
+	}
+	return -1, fmt.Errorf("symbol %q not found", name)
+}
+
+// isCommentLine reports whether a line is a comment or a decorator
+func isCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range []string{"//", "#", "/*", "*", "@"} {
+		if strings.HasPrefix(trimmed, prefix) && !strings.HasPrefix(trimmed, "#include") {
+			return true
+		}
+	}
+	return false
+}
+
+// braceBlockEnd returns the last line of the block opened on or after
+// start. A declaration without braces ends at its first line ending in a
+// semicolon or followed by a blank line.
+func braceBlockEnd(lines []string, start int) int {
+	depth, opened := 0, false
+	for i := start; i < len(lines); i++ {
+		depth += braceDepth(lines[i])
+		if strings.Contains(stripStrings(lines[i]), "{") {
+			opened = true
+		}
+		if opened && depth <= 0 {
+			return i
+		}
+		if !opened {
+			trimmed := strings.TrimSpace(lines[i])
+			if strings.HasSuffix(trimmed, ";") || i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == "" {
+				return i
+			}
+		}
+	}
+	return len(lines) - 1
+}
+
+// braceDepth returns the change in brace depth over a line, ignoring
+// braces in strings and line comments
+func braceDepth(line string) int {
+	depth := 0
+	for _, c := range stripStrings(line) {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// stripStrings removes string and character literals and a trailing line
+// comment
+func stripStrings(line string) string {
+	var b strings.Builder
+	var quote rune
+	escaped := false
+	runes := []rune(line)
+	for i, c := range runes {
+		switch {
+		case quote != 0:
+			if escaped {
+				escaped = false
+			} else if c == '\\' && quote != '`' {
+				escaped = true
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			return b.String()
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// indentBlockEnd returns the last line indented deeper than the line at
+// start, skipping trailing blank lines
+func indentBlockEnd(lines []string, start int) int {
+	indent := indentWidth(lines[start])
+	end := start
+	for i := start + 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			continue
+		}
+		if indentWidth(lines[i]) <= indent {
+			break
+		}
+		end = i
+	}
+	return end
+}
+
+// indentWidth returns the length of a line's leading whitespace
+func indentWidth(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// dedent removes the indentation shared by every non-blank line
+func dedent(lines []string) []string {
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if width := indentWidth(line); common < 0 || width < common {
+			common = width
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if len(line) >= common && common > 0 {
+			line = line[common:]
+		}
+		result[i] = line
+	}
+	return result
+}
+
+// LineRange is an inclusive range of 1-based line numbers. An End of 0
+// runs to the end of the file.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// ParseLineRange parses a range such as "10-40", "10-" or "25"
+func ParseLineRange(text string) (LineRange, error) {
+	invalid := fmt.Errorf("invalid line range %q (use a range such as 10-40, 10- or 25)", text)
+
+	first, last, isRange := strings.Cut(strings.TrimSpace(text), "-")
+	start, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || start < 1 {
+		return LineRange{}, invalid
+	}
+	if !isRange {
+		return LineRange{Start: start, End: start}, nil
+	}
+	if strings.TrimSpace(last) == "" {
+		return LineRange{Start: start}, nil
+	}
+
+	end, err := strconv.Atoi(strings.TrimSpace(last))
+	if err != nil || end < start {
+		return LineRange{}, invalid
+	}
+	return LineRange{Start: start, End: end}, nil
+}
+
+// Extract returns the lines in the range
+func (r LineRange) Extract(lines []string) ([]string, error) {
+	if r.Start > len(lines) {
+		return nil, fmt.Errorf("line %d is past the end of the file (%d lines)", r.Start, len(lines))
+	}
+	end := r.End
+	if end == 0 || end > len(lines) {
+		end = len(lines)
+	}
+	return lines[r.Start-1 : end], nil
+}
+
+// String formats the range as it is written in playlists
+func (r LineRange) String() string {
+	switch r.End {
+	case 0:
+		return fmt.Sprintf("%d-", r.Start)
+	case r.Start:
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
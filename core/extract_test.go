@@ -0,0 +1,182 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    LineRange
+		wantErr bool
+	}{
+		{"10-40", LineRange{Start: 10, End: 40}, false},
+		{" 10 - 40 ", LineRange{Start: 10, End: 40}, false},
+		{"10-", LineRange{Start: 10}, false},
+		{"25", LineRange{Start: 25, End: 25}, false},
+		{"7-7", LineRange{Start: 7, End: 7}, false},
+		{"", LineRange{}, true},
+		{"0-5", LineRange{}, true},
+		{"-5", LineRange{}, true},
+		{"40-10", LineRange{}, true},
+		{"10-x", LineRange{}, true},
+		{"ten", LineRange{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := ParseLineRange(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLineRange(%q) = %+v, want an error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLineRange(%q) returned error: %v", tt.text, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseLineRange(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+			if again, err := ParseLineRange(got.String()); err != nil || again != got {
+				t.Errorf("ParseLineRange(%q) = %+v, %v, want %+v", got.String(), again, err, got)
+			}
+		})
+	}
+}
+
+func TestLineRangeExtract(t *testing.T) {
+	lines := []string{"one", "two", "three", "four"}
+
+	tests := []struct {
+		name    string
+		r       LineRange
+		want    []string
+		wantErr bool
+	}{
+		{"middle", LineRange{Start: 2, End: 3}, []string{"two", "three"}, false},
+		{"single line", LineRange{Start: 4, End: 4}, []string{"four"}, false},
+		{"open end", LineRange{Start: 3}, []string{"three", "four"}, false},
+		{"end past the file", LineRange{Start: 3, End: 10}, []string{"three", "four"}, false},
+		{"start past the file", LineRange{Start: 5, End: 6}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.Extract(lines)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Extract() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Extract() returned error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractSymbol(t *testing.T) {
+	goSource := `package main
+
+// Server handles requests
+type Server struct {
+	addr string
+}
+
+// Start listens on the server's address
+func (s *Server) Start() error {
+	if s.addr == "" {
+		return fmt.Errorf("no address {")
+	}
+	return nil
+}
+
+func main() {
+	s := &Server{}
+	s.Start()
+}`
+
+	pythonSource := `import os
+
+class Reader:
+    @property
+    def name(self):
+        return "reader"
+
+    def read(self, path):
+        if path:
+            return open(path).read()
+
+        return ""
+
+def main():
+    Reader().read("x")`
+
+	jsSource := `const greet = (name) => {
+  return "hi " + name;
+};
+
+function main() {
+  greet("bob");
+}`
+
+	tests := []struct {
+		name     string
+		source   string
+		language string
+		symbol   string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "go method with doc comment", source: goSource, language: "Go", symbol: "Start",
+			want: "// Start listens on the server's address\nfunc (s *Server) Start() error {\n\tif s.addr == \"\" {\n\t\treturn fmt.Errorf(\"no address {\")\n\t}\n\treturn nil\n}",
+		},
+		{
+			name: "go type", source: goSource, language: "Go", symbol: "Server",
+			want: "// Server handles requests\ntype Server struct {\n\taddr string\n}",
+		},
+		{
+			name: "python method is dedented", source: pythonSource, language: "Python", symbol: "read",
+			want: "def read(self, path):\n    if path:\n        return open(path).read()\n\n    return \"\"",
+		},
+		{
+			name: "python decorator is kept", source: pythonSource, language: "Python", symbol: "name",
+			want: "@property\ndef name(self):\n    return \"reader\"",
+		},
+		{
+			name: "javascript arrow function", source: jsSource, language: "JavaScript", symbol: "greet",
+			want: "const greet = (name) => {\n  return \"hi \" + name;\n};",
+		},
+		{
+			name: "calls are not declarations", source: jsSource, language: "JavaScript", symbol: "main",
+			want: "function main() {\n  greet(\"bob\");\n}",
+		},
+		{name: "missing symbol", source: goSource, language: "Go", symbol: "Stop", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractSymbol(strings.Split(tt.source, "\n"), tt.language, tt.symbol)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ExtractSymbol(%q) = %q, want an error", tt.symbol, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractSymbol(%q) returned error: %v", tt.symbol, err)
+			}
+			if joined := strings.Join(got, "\n"); joined != tt.want {
+				t.Errorf("ExtractSymbol(%q) =\n%s\nwant\n%s", tt.symbol, joined, tt.want)
+			}
+		})
+	}
+}
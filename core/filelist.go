@@ -0,0 +1,144 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MaxRecentFiles is how many files the recent list remembers
+const MaxRecentFiles = 10
+
+// FileList stores the recently opened and starred practice files
+type FileList struct {
+	Recent  []string `json:"recent"`  // Most recently opened first
+	Starred []string `json:"starred"` // In the order they were starred
+
+	path string
+}
+
+// DefaultFileListPath returns the file list in the user's config directory
+func DefaultFileListPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "files.json"), nil
+}
+
+// OpenDefaultFileList loads the file list from the default location
+func OpenDefaultFileList() (*FileList, error) {
+	path, err := DefaultFileListPath()
+	if err != nil {
+		return nil, err
+	}
+	return LoadFileList(path)
+}
+
+// LoadFileList reads a file list from path. A missing file is treated as
+// an empty list.
+func LoadFileList(path string) (*FileList, error) {
+	list := &FileList{path: path}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading file list: %v", err)
+	}
+
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("error parsing file list %s: %v", path, err)
+	}
+	return list, nil
+}
+
+// Save writes the file list back to its file
+func (l *FileList) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("error creating config directory: %v", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding file list: %v", err)
+	}
+
+	if err := os.WriteFile(l.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing file list: %v", err)
+	}
+	return nil
+}
+
+// Touch moves a file to the front of the recent list
+func (l *FileList) Touch(file string) {
+	recent := []string{file}
+	for _, path := range l.Recent {
+		if path != file && len(recent) < MaxRecentFiles {
+			recent = append(recent, path)
+		}
+	}
+	l.Recent = recent
+}
+
+// IsStarred reports whether a file is starred
+func (l *FileList) IsStarred(file string) bool {
+	return indexOf(l.Starred, file) >= 0
+}
+
+// ToggleStar stars a file, or unstars it if it was starred. It returns
+// whether the file is now starred.
+func (l *FileList) ToggleStar(file string) bool {
+	if i := indexOf(l.Starred, file); i >= 0 {
+		l.Starred = append(l.Starred[:i:i], l.Starred[i+1:]...)
+		return false
+	}
+	l.Starred = append(l.Starred, file)
+	return true
+}
+
+// indexOf returns the position of a path in a list, or -1
+func indexOf(paths []string, path string) int {
+	for i, p := range paths {
+		if p == path {
+			return i
+		}
+	}
+	return -1
+}
+
+// FileBest is a file's personal bests across its sessions
+type FileBest struct {
+	Sessions      int
+	BestWPM       float64 // Under the definition the bests were worked out with
+	BestAccuracy  float64
+	LastPracticed time.Time
+}
+
+// FileBests returns the personal bests of every file practiced at a
+// difficulty, keyed by path. Records whose WPM cannot be given under the
+// definition still count toward accuracy and the last practice date.
+func FileBests(records []SessionRecord, definition WPMDefinition, difficulty Difficulty) map[string]FileBest {
+	bests := make(map[string]FileBest)
+	for _, record := range records {
+		if record.File == "" || record.Level() != difficulty {
+			continue
+		}
+		best := bests[record.File]
+		best.Sessions++
+		if wpm, ok := record.WPMAs(definition); ok && wpm > best.BestWPM {
+			best.BestWPM = wpm
+		}
+		if record.Accuracy > best.BestAccuracy {
+			best.BestAccuracy = record.Accuracy
+		}
+		if record.Timestamp.After(best.LastPracticed) {
+			best.LastPracticed = record.Timestamp
+		}
+		bests[record.File] = best
+	}
+	return bests
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileBests(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// record returns a session of main.go finished at minute n
+	record := func(n int, definition WPMDefinition, wpm, accuracy float64) SessionRecord {
+		return SessionRecord{
+			Timestamp:     start.Add(time.Duration(n) * time.Minute),
+			File:          "main.go",
+			DurationMS:    60000,
+			WPM:           wpm,
+			Accuracy:      accuracy,
+			Characters:    245,
+			Lines:         5,
+			WPMDefinition: definition,
+		}
+	}
+	hard := record(3, WPMTokens, 90, 100)
+	hard.Difficulty = DifficultyHard
+	noFile := record(4, WPMTokens, 90, 100)
+	noFile.File = ""
+
+	tests := []struct {
+		name       string
+		records    []SessionRecord
+		definition WPMDefinition
+		difficulty Difficulty
+		want       map[string]FileBest
+	}{
+		{
+			name:       "best WPM and accuracy can come from different sessions",
+			records:    []SessionRecord{record(1, WPMTokens, 40, 99), record(2, WPMTokens, 55, 90)},
+			definition: WPMTokens,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{"main.go": {Sessions: 2, BestWPM: 55, BestAccuracy: 99, LastPracticed: start.Add(2 * time.Minute)}},
+		},
+		{
+			name:       "records without a definition used tokens",
+			records:    []SessionRecord{record(1, "", 40, 95)},
+			definition: WPMTokens,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{"main.go": {Sessions: 1, BestWPM: 40, BestAccuracy: 95, LastPracticed: start.Add(time.Minute)}},
+		},
+		{
+			name:       "standard WPM is worked out from characters",
+			records:    []SessionRecord{record(1, WPMTokens, 40, 95)},
+			definition: WPMStandard,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{"main.go": {Sessions: 1, BestWPM: 50, BestAccuracy: 95, LastPracticed: start.Add(time.Minute)}},
+		},
+		{
+			name:       "other definitions count without a WPM",
+			records:    []SessionRecord{record(1, WPMTokens, 40, 95)},
+			definition: WPMLanguage,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{"main.go": {Sessions: 1, BestAccuracy: 95, LastPracticed: start.Add(time.Minute)}},
+		},
+		{
+			name:       "other difficulties and records without a file are left out",
+			records:    []SessionRecord{record(1, WPMTokens, 40, 95), hard, noFile},
+			definition: WPMTokens,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{"main.go": {Sessions: 1, BestWPM: 40, BestAccuracy: 95, LastPracticed: start.Add(time.Minute)}},
+		},
+		{
+			name:       "only the difficulty asked for",
+			records:    []SessionRecord{record(1, WPMTokens, 40, 95), hard},
+			definition: WPMTokens,
+			difficulty: DifficultyHard,
+			want:       map[string]FileBest{"main.go": {Sessions: 1, BestWPM: 90, BestAccuracy: 100, LastPracticed: start.Add(3 * time.Minute)}},
+		},
+		{
+			name:       "no records",
+			definition: WPMTokens,
+			difficulty: DifficultyNormal,
+			want:       map[string]FileBest{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FileBests(tt.records, tt.definition, tt.difficulty)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FileBests() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
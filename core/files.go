@@ -0,0 +1,203 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sample is a built-in practice file
+type Sample struct {
+	File        string // File name in the assets folder
+	Description string
+	Aliases     []string // Shortcuts that select the sample
+}
+
+// Samples lists the built-in practice files
+var Samples = []Sample{
+	{"sample.go", "Go calculator example", []string{"go"}},
+	{"sample.py", "Python data processor", []string{"py", "python"}},
+	{"sample.js", "JavaScript task manager", []string{"js", "javascript"}},
+	{"sample.cpp", "C++ grade system", []string{"cpp", "c++"}},
+}
+
+// sampleFiles holds the built-in samples compiled into the binary
+var sampleFiles fs.FS
+
+// SetSampleFiles sets the built-in samples compiled into the binary, with
+// each sample at the root of files
+func SetSampleFiles(files fs.FS) {
+	sampleFiles = files
+}
+
+// FindSample returns the sample selected by a shortcut such as "go" or
+// "sample.py"
+func FindSample(name string) (Sample, bool) {
+	name = strings.ToLower(name)
+	for _, sample := range Samples {
+		if name == sample.File {
+			return sample, true
+		}
+		for _, alias := range sample.Aliases {
+			if name == alias {
+				return sample, true
+			}
+		}
+	}
+	return Sample{}, false
+}
+
+// ExpandFilePath resolves a sample shortcut to its asset file and any other
+// input to an absolute path, expanding a leading ~ to the home directory
+func ExpandFilePath(input string) string {
+	if sample, ok := FindSample(input); ok {
+		return FindAsset(sample.File)
+	}
+
+	if input == "~" || strings.HasPrefix(input, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			input = filepath.Join(home, strings.TrimPrefix(input, "~"))
+		}
+	}
+	if absPath, err := filepath.Abs(input); err == nil {
+		return absPath
+	}
+	return input
+}
+
+// FindAsset finds an asset file next to the binary, in an installation
+// share directory or in the current directory. Samples found in none of
+// them are loaded from the copies compiled into the binary.
+func FindAsset(filename string) string {
+	if path, ok := InstalledAsset(filename); ok {
+		return path
+	}
+
+	if path, err := extractSample(filename); err == nil {
+		return path
+	}
+
+	// If no asset file found, return the relative path as fallback
+	return filepath.Join("assets", filename)
+}
+
+// InstalledAsset returns the path of an asset file next to the binary, in
+// an installation share directory or in the current directory
+func InstalledAsset(filename string) (string, bool) {
+	var possiblePaths []string
+
+	if execPath, err := os.Executable(); err == nil {
+		execDir := filepath.Dir(execPath)
+		possiblePaths = append(possiblePaths,
+			// Assets in same directory as binary (for development)
+			filepath.Join(execDir, "assets", filename),
+			// Assets relative to binary (for installed version)
+			filepath.Join(execDir, "..", "share", "syntaxrush", "assets", filename),
+		)
+	}
+	possiblePaths = append(possiblePaths,
+		// System-wide assets
+		filepath.Join("/usr", "share", "syntaxrush", "assets", filename),
+		// Local share
+		filepath.Join("/usr", "local", "share", "syntaxrush", "assets", filename),
+		// Current directory
+		filepath.Join("assets", filename),
+	)
+
+	for _, path := range possiblePaths {
+		if _, err := os.Stat(path); err == nil {
+			if absPath, err := filepath.Abs(path); err == nil {
+				return absPath, true
+			}
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// ReadSampleHead returns up to n lines from the start of a sample compiled
+// into the binary, without copying it out
+func ReadSampleHead(filename string, n int) ([]string, error) {
+	if sampleFiles == nil {
+		return nil, fmt.Errorf("no built-in samples")
+	}
+	file, err := sampleFiles.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readHead(file, n)
+}
+
+// extractSample writes a sample compiled into the binary to the user's
+// cache directory and returns its path. Sessions, history and the file
+// browser refer to files by path, so the sample needs one.
+func extractSample(filename string) (string, error) {
+	if sampleFiles == nil {
+		return "", fmt.Errorf("no built-in samples")
+	}
+	data, err := fs.ReadFile(sampleFiles, filename)
+	if err != nil {
+		return "", err
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating cache directory: %v", err)
+	}
+	dir := filepath.Join(cacheDir, "syntaxrush", "assets")
+	path := filepath.Join(dir, filename)
+
+	// Copies from an older binary are replaced
+	if existing, err := os.ReadFile(path); err == nil && bytes.Equal(existing, data) {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating sample directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("error writing sample: %v", err)
+	}
+	return path, nil
+}
+
+// RecentFiles returns the files of the most recent sessions, newest first,
+// without duplicates
+func RecentFiles(records []SessionRecord, limit int) []string {
+	var files []string
+	seen := make(map[string]bool)
+	for i := len(records) - 1; i >= 0 && len(files) < limit; i-- {
+		file := records[i].File
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+		files = append(files, file)
+	}
+	return files
+}
+
+// ReadHead returns up to n lines from the start of a file
+func ReadHead(path string, n int) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readHead(file, n)
+}
+
+// readHead returns up to n lines from the start of r
+func readHead(r io.Reader, n int) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for len(lines) < n && scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,185 @@
+package core
+
+import "time"
+
+// FingerStats is how accurately and quickly one finger types
+type FingerStats struct {
+	Finger    Finger
+	Keys      int           // Keys the finger was expected to press
+	Mistakes  int           // Of those, keys typed wrong
+	Shifts    int           // Times the finger held shift for the other hand
+	Timed     int           // Correct keys whose time from the previous key was measured
+	TotalTime time.Duration // Sum of those times
+}
+
+// Accuracy returns the percentage of the finger's keys typed correctly
+func (f FingerStats) Accuracy() float64 {
+	if f.Keys == 0 {
+		return 100
+	}
+	return float64(f.Keys-f.Mistakes) / float64(f.Keys) * 100
+}
+
+// AverageTime returns the average time from the previous key to a correct
+// key pressed by the finger
+func (f FingerStats) AverageTime() time.Duration {
+	if f.Timed == 0 {
+		return 0
+	}
+	return f.TotalTime / time.Duration(f.Timed)
+}
+
+// add merges the statistics of another finger into f
+func (f *FingerStats) add(other FingerStats) {
+	f.Keys += other.Keys
+	f.Mistakes += other.Mistakes
+	f.Shifts += other.Shifts
+	f.Timed += other.Timed
+	f.TotalTime += other.TotalTime
+}
+
+// FingerAnalysis is a keystroke stream broken down by finger on a layout
+type FingerAnalysis struct {
+	Layout     string
+	Fingers    []FingerStats // One per finger, in the order of Fingers
+	Unmapped   int           // Keys the layout has no key for
+	Bigrams    int           // Pairs of consecutive correct keys, not counting space
+	SameFinger int           // Pairs typed by one finger on two different keys
+}
+
+// AnalyzeFingers maps every keystroke to the finger that presses it on the
+// layout. A mistake is charged to the finger of the key that was expected,
+// since that is the reach that failed. Backspace is left out, and timings
+// and bigrams restart after a mistake, Enter or a pause.
+func AnalyzeFingers(layout *KeyboardLayout, streams [][]Keystroke) FingerAnalysis {
+	analysis := FingerAnalysis{Layout: layout.Name, Fingers: make([]FingerStats, len(Fingers))}
+	for _, finger := range Fingers {
+		analysis.Fingers[finger].Finger = finger
+	}
+
+	for _, stream := range streams {
+		// previous is the last correct key of the current run
+		var previous *Key
+		for i, keystroke := range stream {
+			if keystroke.Char == KeyBackspace || keystroke.Char == KeySkip {
+				previous = nil
+				continue
+			}
+
+			key, ok := targetKey(layout, keystroke)
+			if !ok {
+				analysis.Unmapped++
+				previous = nil
+				continue
+			}
+
+			finger := &analysis.Fingers[key.Finger]
+			finger.Keys++
+			if key.Shift {
+				analysis.Fingers[key.ShiftFinger()].Shifts++
+			}
+			if !keystroke.Correct {
+				finger.Mistakes++
+				previous = nil
+				continue
+			}
+
+			if previous != nil && i > 0 {
+				if gap := keystroke.Time - stream[i-1].Time; gap <= maxKeyLatency {
+					finger.Timed++
+					finger.TotalTime += gap
+
+					if previous.Finger != Thumb && key.Finger != Thumb {
+						analysis.Bigrams++
+						if previous.Finger == key.Finger && previous.Position() != key.Position() {
+							analysis.SameFinger++
+						}
+					}
+				}
+			}
+
+			previous = &key
+			if keystroke.Char == KeyEnter {
+				previous = nil
+			}
+		}
+	}
+	return analysis
+}
+
+// targetKey returns the key a keystroke was meant to press: the expected
+// key for mistakes, so the reach that failed is charged
+func targetKey(layout *KeyboardLayout, keystroke Keystroke) (Key, bool) {
+	char := keystroke.Char
+	if !keystroke.Correct && keystroke.Expected != 0 {
+		char = keystroke.Expected
+	}
+	return layout.Key(char)
+}
+
+// KeyCount is how often a key was meant to be pressed and how often it was
+// mistyped
+type KeyCount struct {
+	Presses  int
+	Mistakes int
+}
+
+// ErrorRate returns the percentage of presses that were mistakes
+func (c KeyCount) ErrorRate() float64 {
+	if c.Presses == 0 {
+		return 0
+	}
+	return float64(c.Mistakes) / float64(c.Presses) * 100
+}
+
+// CountKeys tallies a keystroke stream by key position on the layout.
+// Like AnalyzeFingers, mistakes count against the expected key and
+// backspace is left out.
+func CountKeys(layout *KeyboardLayout, keystrokes []Keystroke) map[KeyPosition]KeyCount {
+	counts := make(map[KeyPosition]KeyCount)
+	for _, keystroke := range keystrokes {
+		if keystroke.Char == KeyBackspace || keystroke.Char == KeySkip {
+			continue
+		}
+		key, ok := targetKey(layout, keystroke)
+		if !ok {
+			continue
+		}
+		count := counts[key.Position()]
+		count.Presses++
+		if !keystroke.Correct {
+			count.Mistakes++
+		}
+		counts[key.Position()] = count
+	}
+	return counts
+}
+
+// SameFingerRate returns the percentage of bigrams typed by one finger on
+// two different keys
+func (a FingerAnalysis) SameFingerRate() float64 {
+	if a.Bigrams == 0 {
+		return 0
+	}
+	return float64(a.SameFinger) / float64(a.Bigrams) * 100
+}
+
+// Hand returns the combined statistics of the fingers of one hand
+func (a FingerAnalysis) Hand(hand Hand) FingerStats {
+	var stats FingerStats
+	for _, finger := range a.Fingers {
+		if finger.Finger.Hand() == hand {
+			stats.add(finger)
+		}
+	}
+	return stats
+}
+
+// Keys returns the number of keystrokes mapped to a finger
+func (a FingerAnalysis) Keys() int {
+	total := 0
+	for _, finger := range a.Fingers {
+		total += finger.Keys
+	}
+	return total
+}
@@ -0,0 +1,302 @@
+package core
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SessionRecord is a completed session as stored in the history file
+type SessionRecord struct {
+	Timestamp  time.Time `json:"timestamp"`
+	File       string    `json:"file"`
+	Language   string    `json:"language"`
+	DurationMS int64     `json:"duration_ms"`
+	WPM        float64   `json:"wpm"`
+	CPM        float64   `json:"cpm"`
+	Accuracy   float64   `json:"accuracy"`
+	Mistakes   int       `json:"mistakes"`
+	Characters int       `json:"characters"`
+	Lines      int       `json:"lines"`
+	PeakPower  float64   `json:"peak_power"`
+	MaxStreak  int       `json:"max_streak"`
+
+	// Timed tests record their length; their WPM is in standard
+	// five-character words
+	Mode       string `json:"mode,omitempty"`
+	TimeLimitS int    `json:"time_limit_s,omitempty"`
+
+	// Difficulty decides how much of each line was typed; records written
+	// before it existed were normal
+	Difficulty Difficulty `json:"difficulty,omitempty"`
+
+	// WPMDefinition says how WPM counts words; records written before it
+	// existed used whitespace tokens
+	WPMDefinition WPMDefinition `json:"wpm_definition,omitempty"`
+	RawWPM        float64       `json:"raw_wpm,omitempty"`
+	NetWPM        float64       `json:"net_wpm,omitempty"`
+	KPM           float64       `json:"kpm,omitempty"`
+
+	// ContentHash identifies the lines practiced, so results for files
+	// that share a name but not their code are kept apart
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// Keystrokes is every key pressed with its timing, for latency analysis
+	Keystrokes *KeyLog `json:"keystrokes,omitempty"`
+}
+
+// ModeTest marks records of timed tests
+const ModeTest = "test"
+
+// TimeLimit returns the length of a timed test, or 0 for practice sessions
+func (r SessionRecord) TimeLimit() time.Duration {
+	return time.Duration(r.TimeLimitS) * time.Second
+}
+
+// Level returns the difficulty the session was typed at
+func (r SessionRecord) Level() Difficulty {
+	if r.Difficulty == "" {
+		return DifficultyNormal
+	}
+	return r.Difficulty
+}
+
+// Definition returns how the record's WPM counts words
+func (r SessionRecord) Definition() WPMDefinition {
+	if r.WPMDefinition == "" {
+		return WPMTokens
+	}
+	return r.WPMDefinition
+}
+
+// WPMAs returns the record's WPM under another definition. Standard WPM
+// can be worked out from any record; token counts cannot, so records
+// scored another way report false.
+func (r SessionRecord) WPMAs(definition WPMDefinition) (float64, bool) {
+	if r.Definition() == definition {
+		return r.WPM, true
+	}
+	if definition == WPMStandard {
+		// Every character plus the Enter at the end of each line
+		return StandardWPM(r.Characters+r.Lines, r.Duration()), true
+	}
+	return 0, false
+}
+
+// Duration returns the session length
+func (r SessionRecord) Duration() time.Duration {
+	return time.Duration(r.DurationMS) * time.Millisecond
+}
+
+// ContentHash returns the hex SHA-256 of lines joined by newlines
+func ContentHash(lines []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewSessionRecord builds a history record from a finished session
+func NewSessionRecord(file string, session *Session) SessionRecord {
+	stats := session.Stats()
+	mpiStats := session.MPI().GetStats()
+
+	record := SessionRecord{
+		Timestamp:  time.Now(),
+		File:       file,
+		Language:   LanguageForFile(file),
+		DurationMS: stats.TotalTime.Milliseconds(),
+		WPM:        stats.WPM,
+		CPM:        stats.CPM,
+		Accuracy:   stats.Accuracy,
+		Mistakes:   stats.TotalMistakes,
+		Characters: stats.TotalCharacters,
+		Lines:      stats.LinesCompleted,
+		Difficulty: session.Difficulty(),
+
+		WPMDefinition: stats.WPMDefinition,
+		RawWPM:        stats.RawWPM,
+		NetWPM:        stats.NetWPM,
+		KPM:           stats.KPM,
+
+		ContentHash: ContentHash(session.Lines()),
+		Keystrokes:  session.KeyLog(),
+	}
+
+	if limit := session.TimeLimit(); limit > 0 {
+		record.Mode = ModeTest
+		record.TimeLimitS = int(limit.Seconds())
+		record.WPM = session.StandardWPM()
+		record.WPMDefinition = WPMStandard
+	}
+
+	if peak, ok := mpiStats["peak_power"].(float64); ok {
+		record.PeakPower = peak
+	}
+	if streak, ok := mpiStats["max_streak"].(int); ok {
+		record.MaxStreak = streak
+	}
+
+	return record
+}
+
+// History stores completed sessions as JSON lines on disk
+type History struct {
+	path string
+}
+
+// NewHistory creates a history store backed by the given file
+func NewHistory(path string) *History {
+	return &History{path: path}
+}
+
+// DefaultHistoryPath returns the history file in the user's config directory
+func DefaultHistoryPath() (string, error) {
+	dir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// OpenDefaultHistory opens the history store at the default location
+func OpenDefaultHistory() (*History, error) {
+	path, err := DefaultHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	return NewHistory(path), nil
+}
+
+// Path returns the file backing the history store
+func (h *History) Path() string {
+	return h.path
+}
+
+// Append adds a session record to the end of the history file
+func (h *History) Append(record SessionRecord) error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("error creating history directory: %v", err)
+	}
+
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding session: %v", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing history: %v", err)
+	}
+	return nil
+}
+
+// Load reads all session records, oldest first. A missing history file is
+// treated as an empty history.
+func (h *History) Load() ([]SessionRecord, error) {
+	file, err := os.Open(h.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	var records []SessionRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record SessionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("error reading history line %d: %v", lineNum, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading history: %v", err)
+	}
+	return records, nil
+}
+
+// FilterRecords returns the records matching a language and file. Empty
+// filters match everything; languages accept shortcuts like "py", and files
+// match on their base name or full path.
+func FilterRecords(records []SessionRecord, language, file string) []SessionRecord {
+	language = LanguageFromName(language)
+
+	var filtered []SessionRecord
+	for _, record := range records {
+		if language != "" && !strings.EqualFold(record.Language, language) {
+			continue
+		}
+		if file != "" && !strings.EqualFold(record.File, file) &&
+			!strings.EqualFold(filepath.Base(record.File), file) {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+// TestDurations are the supported timed test lengths
+var TestDurations = []time.Duration{15 * time.Second, 30 * time.Second, 60 * time.Second, 120 * time.Second}
+
+// TestLeaderboard returns the timed tests of one length, best WPM first,
+// with accuracy breaking ties. An empty language matches every language.
+// Tests that fail validation are left out; tests recorded before
+// keystrokes were kept cannot be checked and stay in.
+func TestLeaderboard(records []SessionRecord, limit time.Duration, language string, difficulty Difficulty) []SessionRecord {
+	var board []SessionRecord
+	for _, record := range FilterRecords(records, language, "") {
+		if record.Mode != ModeTest || record.TimeLimit() != limit || record.Level() != difficulty {
+			continue
+		}
+		if err := record.Validate(); err == nil || err == ErrUnverifiable {
+			board = append(board, record)
+		}
+	}
+
+	sort.SliceStable(board, func(i, j int) bool {
+		if board[i].WPM != board[j].WPM {
+			return board[i].WPM > board[j].WPM
+		}
+		return board[i].Accuracy > board[j].Accuracy
+	})
+	return board
+}
+
+// RollingAverage averages a value over the records within window before end.
+// It returns the average and the number of records it covers.
+func RollingAverage(records []SessionRecord, end time.Time, window time.Duration, value func(SessionRecord) float64) (float64, int) {
+	start := end.Add(-window)
+	total := 0.0
+	count := 0
+
+	for _, record := range records {
+		if record.Timestamp.After(start) && !record.Timestamp.After(end) {
+			total += value(record)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, 0
+	}
+	return total / float64(count), count
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// KeyAction is a command that can be bound to a key while typing
+type KeyAction string
+
+const (
+	ActionPalette     KeyAction = "palette"      // Open the command palette
+	ActionRetry       KeyAction = "retry"        // Start the session over
+	ActionOpen        KeyAction = "open"         // Open the file picker
+	ActionMenu        KeyAction = "menu"         // Go back to the welcome screen
+	ActionSkipLine    KeyAction = "skip_line"    // Move on without typing the rest of the line
+	ActionRestartLine KeyAction = "restart_line" // Start the session over at the current line
+	ActionPowerGraph  KeyAction = "power_graph"  // Show or hide the power graph
+	ActionKeyboard    KeyAction = "keyboard"     // Show or hide the on-screen keyboard
+	ActionMute        KeyAction = "mute"         // Turn sound off or on
+)
+
+// KeyActions lists every action that can be bound, in the order they are shown
+var KeyActions = []KeyAction{
+	ActionPalette, ActionRetry, ActionOpen, ActionSkipLine, ActionRestartLine,
+	ActionPowerGraph, ActionKeyboard, ActionMute, ActionMenu,
+}
+
+// DefaultKeys are the keys bound to each action when the config does not
+// set them. Actions without a key are still run from the palette.
+var DefaultKeys = map[KeyAction]string{
+	ActionPalette:     "ctrl+p",
+	ActionRetry:       "ctrl+r",
+	ActionOpen:        "ctrl+u",
+	ActionMenu:        "esc",
+	ActionSkipLine:    "ctrl+n",
+	ActionRestartLine: "ctrl+l",
+	ActionPowerGraph:  "ctrl+g",
+	ActionKeyboard:    "ctrl+k",
+	ActionMute:        "ctrl+s",
+}
+
+// reservedKeys type code or always leave the session, so they cannot be bound
+var reservedKeys = []string{"enter", "backspace", "tab", "space", "ctrl+c"}
+
+// terminalAliases are keys terminals send as the same byte as a key used
+// for typing
+var terminalAliases = map[string]string{"ctrl+h": "backspace", "ctrl+i": "tab", "ctrl+m": "enter"}
+
+// checkKey returns an error if key cannot be bound to an action. Keys are
+// named the way the terminal reports them, e.g. "ctrl+p", "alt+j" or "f2".
+func checkKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("empty key name (use \"none\" to unbind)")
+	}
+	for _, reserved := range reservedKeys {
+		if key == reserved {
+			return fmt.Errorf("%s is needed for typing and cannot be bound", key)
+		}
+	}
+	if alias, ok := terminalAliases[key]; ok {
+		return fmt.Errorf("terminals send %s as %s, so it cannot be bound", key, alias)
+	}
+	if utf8.RuneCountInString(key) == 1 || strings.HasPrefix(key, "shift+") {
+		return fmt.Errorf("%q types a character; use a key with ctrl+ or alt+, or a function key", key)
+	}
+	return nil
+}
+
+// Key returns the key bound to an action, or "" if it is unbound
+func (c *Config) Key(action KeyAction) string {
+	if key, ok := c.Keys[string(action)]; ok {
+		return key
+	}
+	return DefaultKeys[action]
+}
+
+// KeyBindings returns the key bound to every action that has one
+func (c *Config) KeyBindings() map[KeyAction]string {
+	bindings := make(map[KeyAction]string)
+	for _, action := range KeyActions {
+		if key := c.Key(action); key != "" {
+			bindings[action] = key
+		}
+	}
+	return bindings
+}
+
+// setKey binds key to an action; "none" unbinds it. A key can only run
+// one action.
+func (c *Config) setKey(action KeyAction, key string) error {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if key == "none" {
+		key = ""
+	} else if err := checkKey(key); err != nil {
+		return fmt.Errorf("invalid keys.%s: %v", action, err)
+	} else {
+		for _, other := range KeyActions {
+			if other != action && c.Key(other) == key {
+				return fmt.Errorf("invalid keys.%s: %s is already bound to %s", action, key, other)
+			}
+		}
+	}
+
+	if c.Keys == nil {
+		c.Keys = make(map[string]string)
+	}
+	c.Keys[string(action)] = key
+	return nil
+}
+
+// checkKeys returns an error if the config binds an unknown action, a key
+// that cannot be bound, or one key to several actions
+func (c *Config) checkKeys() error {
+	names := make([]string, 0, len(c.Keys))
+	for name := range c.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := DefaultKeys[KeyAction(name)]; !ok {
+			return fmt.Errorf("unknown action keys.%s", name)
+		}
+		if key := c.Keys[name]; key != "" {
+			if err := checkKey(key); err != nil {
+				return fmt.Errorf("invalid keys.%s: %v", name, err)
+			}
+		}
+	}
+
+	bound := make(map[string]KeyAction)
+	for _, action := range KeyActions {
+		key := c.Key(action)
+		if key == "" {
+			continue
+		}
+		if other, ok := bound[key]; ok {
+			return fmt.Errorf("invalid keys.%s: %s is already bound to %s", action, key, other)
+		}
+		bound[key] = action
+	}
+	return nil
+}
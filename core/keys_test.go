@@ -0,0 +1,87 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		wantErr string // Part of the error, "" for a key that can be bound
+	}{
+		{"ctrl+p", ""},
+		{"alt+j", ""},
+		{"f2", ""},
+		{"esc", ""},
+		{"", "empty key name"},
+		{"enter", "needed for typing"},
+		{"tab", "needed for typing"},
+		{"ctrl+c", "needed for typing"},
+		{"ctrl+h", "as backspace"},
+		{"ctrl+i", "as tab"},
+		{"ctrl+m", "as enter"},
+		{"x", "types a character"},
+		{"é", "types a character"},
+		{"shift+a", "types a character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			err := checkKey(tt.key)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkKey(%q) = %v, want nil", tt.key, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkKey(%q) = %v, want an error containing %q", tt.key, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		keys    map[string]string
+		wantErr string // Part of the error, "" for valid bindings
+	}{
+		{"defaults", nil, ""},
+		{"rebound", map[string]string{"palette": "f1", "retry": "alt+r"}, ""},
+		{"unbound", map[string]string{"mute": ""}, ""},
+		{"swapped", map[string]string{"palette": "ctrl+r", "retry": "ctrl+p"}, ""},
+		{"default key freed for another action", map[string]string{"mute": "", "keyboard": "ctrl+s"}, ""},
+		{"unknown action", map[string]string{"jump": "ctrl+j"}, "unknown action keys.jump"},
+		{"key that types", map[string]string{"retry": "r"}, "invalid keys.retry"},
+		{"terminal alias", map[string]string{"open": "ctrl+m"}, "invalid keys.open"},
+		{"clashes with a default", map[string]string{"mute": "ctrl+p"}, "ctrl+p is already bound to palette"},
+		{"clashes with another binding", map[string]string{"retry": "f5", "open": "f5"}, "f5 is already bound to retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Keys = tt.keys
+			err := config.checkKeys()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkKeys() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkKeys() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyBindingsAreCopies(t *testing.T) {
+	bindings := DefaultConfig().KeyBindings()
+	bindings[ActionPalette] = "f1"
+	if DefaultKeys[ActionPalette] != "ctrl+p" {
+		t.Fatalf("DefaultKeys[palette] = %q after changing a copy, want ctrl+p", DefaultKeys[ActionPalette])
+	}
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Keys recorded for Enter, backspace and skipped lines in a keystroke stream
+const (
+	KeyEnter     = '\n'
+	KeyBackspace = '\b'
+	KeySkip      = '\x1f' // The rest of the line was skipped
+)
+
+// Keystroke is one key press in a session
+type Keystroke struct {
+	Char     rune          // Typed character, KeyEnter, KeyBackspace or KeySkip
+	Time     time.Duration // From the start of the session
+	Correct  bool
+	Expected rune // Character the line expected, or 0 past its end; only kept for mistakes
+}
+
+// KeyLog is a keystroke stream in the compact form stored in history
+type KeyLog struct {
+	Keys     string  `json:"keys"`
+	DelaysMS []int64 `json:"delays_ms"`          // Time since the previous key, or since the start for the first
+	Mistakes []int   `json:"mistakes,omitempty"` // Indexes of incorrect keys
+	Expected string  `json:"expected,omitempty"` // Expected character of each mistake, 0 if none
+
+	// LineLengths is the length of each line typed, so the stream can be
+	// replayed through the metrics engine
+	LineLengths []int `json:"line_lengths,omitempty"`
+	Pastes      int   `json:"pastes,omitempty"` // Text pasted into the session, which is not typed
+}
+
+// NewKeyLog packs a keystroke stream for storage
+func NewKeyLog(keystrokes []Keystroke) *KeyLog {
+	if len(keystrokes) == 0 {
+		return nil
+	}
+
+	log := &KeyLog{DelaysMS: make([]int64, len(keystrokes))}
+	keys := make([]rune, len(keystrokes))
+	var expected []rune
+	var previous int64
+	for i, key := range keystrokes {
+		// Delays are taken between rounded times so they add up exactly
+		keys[i] = key.Char
+		log.DelaysMS[i] = key.Time.Milliseconds() - previous
+		previous = key.Time.Milliseconds()
+		if !key.Correct {
+			log.Mistakes = append(log.Mistakes, i)
+			expected = append(expected, key.Expected)
+		}
+	}
+	log.Keys = string(keys)
+	if len(expected) > 0 {
+		log.Expected = string(expected)
+	}
+	return log
+}
+
+// Keystrokes unpacks the stream. Times are rebuilt from the delays, so
+// they are rounded to the millisecond.
+func (l *KeyLog) Keystrokes() []Keystroke {
+	if l == nil {
+		return nil
+	}
+
+	keys := []rune(l.Keys)
+	if len(l.DelaysMS) < len(keys) {
+		keys = keys[:len(l.DelaysMS)]
+	}
+
+	// Sessions saved before expected characters were kept have none
+	expected := []rune(l.Expected)
+	mistakes := make(map[int]rune, len(l.Mistakes))
+	for n, i := range l.Mistakes {
+		mistakes[i] = 0
+		if n < len(expected) {
+			mistakes[i] = expected[n]
+		}
+	}
+
+	keystrokes := make([]Keystroke, len(keys))
+	var elapsed time.Duration
+	for i, char := range keys {
+		elapsed += time.Duration(l.DelaysMS[i]) * time.Millisecond
+		want, mistake := mistakes[i]
+		keystrokes[i] = Keystroke{Char: char, Time: elapsed, Correct: !mistake, Expected: want}
+	}
+	return keystrokes
+}
+
+// maxKeyLatency is the longest gap counted as typing; longer pauses are
+// breaks, not hesitation, and are left out of the analysis
+const maxKeyLatency = 3 * time.Second
+
+// KeyAnalysis describes how quickly keys follow each other
+type KeyAnalysis struct {
+	Keystrokes int             // Keys analyzed
+	Latencies  int             // Gaps between keys that were measured
+	P50        time.Duration   // Median time between keys
+	P90        time.Duration   // 90th percentile
+	P99        time.Duration   // 99th percentile
+	Bigrams    []NgramSpeed    // Slowest first
+	Trigrams   []NgramSpeed    // Slowest first
+	Characters []NgramSpeed    // Characters with the longest hesitation first
+	Pauses     int             // Gaps longer than maxKeyLatency that were left out
+	sorted     []time.Duration // Measured gaps, shortest first
+}
+
+// NgramSpeed is the average time taken to type a character sequence,
+// measured from its first key to its last. For single characters it is the
+// time from the previous key.
+type NgramSpeed struct {
+	Text    string
+	Count   int
+	Average time.Duration
+}
+
+// ngramTimes collects the timings of one n-gram length
+type ngramTimes map[string][]time.Duration
+
+// AnalyzeKeystrokes measures the gaps between keys over one or more
+// keystroke streams. Sequences are only timed across correct keys on one
+// line, and must occur at least minCount times to be listed.
+func AnalyzeKeystrokes(streams [][]Keystroke, minCount int) KeyAnalysis {
+	var analysis KeyAnalysis
+	chars := ngramTimes{}
+	bigrams := ngramTimes{}
+	trigrams := ngramTimes{}
+
+	for _, stream := range streams {
+		analysis.Keystrokes += len(stream)
+
+		// run holds the current run of correct keys on one line
+		var run []Keystroke
+		for i, key := range stream {
+			if i > 0 {
+				gap := key.Time - stream[i-1].Time
+				if gap > maxKeyLatency {
+					analysis.Pauses++
+					run = nil
+				} else {
+					analysis.sorted = append(analysis.sorted, gap)
+				}
+			}
+
+			if !key.Correct || key.Char == KeyBackspace || key.Char == KeyEnter || key.Char == KeySkip {
+				run = nil
+				continue
+			}
+
+			run = append(run, key)
+			n := len(run)
+			if n >= 2 {
+				chars.add(string(key.Char), key.Time-run[n-2].Time)
+				bigrams.add(string([]rune{run[n-2].Char, key.Char}), key.Time-run[n-2].Time)
+			}
+			if n >= 3 {
+				trigrams.add(string([]rune{run[n-3].Char, run[n-2].Char, key.Char}), key.Time-run[n-3].Time)
+			}
+		}
+	}
+
+	sort.Slice(analysis.sorted, func(i, j int) bool {
+		return analysis.sorted[i] < analysis.sorted[j]
+	})
+	analysis.Latencies = len(analysis.sorted)
+	analysis.P50 = analysis.Percentile(50)
+	analysis.P90 = analysis.Percentile(90)
+	analysis.P99 = analysis.Percentile(99)
+
+	analysis.Characters = chars.slowest(minCount)
+	analysis.Bigrams = bigrams.slowest(minCount)
+	analysis.Trigrams = trigrams.slowest(minCount)
+	return analysis
+}
+
+// Percentile returns the gap between keys that p percent of gaps are
+// shorter than or equal to, using the nearest-rank method
+func (a KeyAnalysis) Percentile(p float64) time.Duration {
+	if len(a.sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p/100*float64(len(a.sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(a.sorted) {
+		rank = len(a.sorted) - 1
+	}
+	return a.sorted[rank]
+}
+
+// add records one timing of an n-gram
+func (t ngramTimes) add(text string, d time.Duration) {
+	t[text] = append(t[text], d)
+}
+
+// slowest returns the n-grams seen at least minCount times, slowest
+// average first
+func (t ngramTimes) slowest(minCount int) []NgramSpeed {
+	var speeds []NgramSpeed
+	for text, times := range t {
+		if len(times) < minCount {
+			continue
+		}
+		var total time.Duration
+		for _, d := range times {
+			total += d
+		}
+		speeds = append(speeds, NgramSpeed{Text: text, Count: len(times), Average: total / time.Duration(len(times))})
+	}
+
+	sort.Slice(speeds, func(i, j int) bool {
+		if speeds[i].Average != speeds[j].Average {
+			return speeds[i].Average > speeds[j].Average
+		}
+		return speeds[i].Text < speeds[j].Text
+	})
+	return speeds
+}
@@ -0,0 +1,294 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Finger identifies the finger that presses a key in touch typing
+type Finger int
+
+const (
+	LeftPinky Finger = iota
+	LeftRing
+	LeftMiddle
+	LeftIndex
+	Thumb
+	RightIndex
+	RightMiddle
+	RightRing
+	RightPinky
+)
+
+// Fingers lists every finger from left to right
+var Fingers = []Finger{LeftPinky, LeftRing, LeftMiddle, LeftIndex, Thumb, RightIndex, RightMiddle, RightRing, RightPinky}
+
+// fingerNames are the display names of each finger
+var fingerNames = []string{"left pinky", "left ring", "left middle", "left index", "thumb", "right index", "right middle", "right ring", "right pinky"}
+
+// String returns the finger's name, e.g. "left pinky"
+func (f Finger) String() string {
+	return fingerNames[f]
+}
+
+// Short returns a two-letter abbreviation, e.g. "LP"
+func (f Finger) Short() string {
+	if f == Thumb {
+		return "TH"
+	}
+	name := strings.Fields(f.String())
+	return strings.ToUpper(name[0][:1] + name[1][:1])
+}
+
+// Hand identifies a hand
+type Hand int
+
+const (
+	LeftHand Hand = iota
+	RightHand
+	BothHands // Thumbs share the space bar
+)
+
+// String returns the hand's name
+func (h Hand) String() string {
+	switch h {
+	case LeftHand:
+		return "left"
+	case RightHand:
+		return "right"
+	default:
+		return "both"
+	}
+}
+
+// Hand returns the hand a finger belongs to
+func (f Finger) Hand() Hand {
+	switch {
+	case f < Thumb:
+		return LeftHand
+	case f > Thumb:
+		return RightHand
+	default:
+		return BothHands
+	}
+}
+
+// keyFingers assigns a finger to each key of the four character rows of a
+// standard staggered keyboard, from the number row down
+var keyFingers = [4][]Finger{
+	{LeftPinky, LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky, RightPinky},
+	{LeftPinky, LeftRing, LeftMiddle, LeftIndex, LeftIndex, RightIndex, RightIndex, RightMiddle, RightRing, RightPinky},
+}
+
+// SpaceRow is the row of the space bar, below the four character rows
+const SpaceRow = 4
+
+// Key is where a character is typed on a keyboard layout
+type Key struct {
+	Row    int // 0 is the number row and SpaceRow the space bar
+	Col    int // Tab is left of row 1 (-1) and Enter right of row 2
+	Finger Finger
+	Shift  bool // The character needs shift, held by the other hand's pinky
+}
+
+// KeyPosition is a physical key: its row and column
+type KeyPosition struct {
+	Row int
+	Col int
+}
+
+// Position returns the physical key, whether or not shift is held
+func (k Key) Position() KeyPosition {
+	return KeyPosition{Row: k.Row, Col: k.Col}
+}
+
+// ShiftFinger returns the pinky that holds shift for the key
+func (k Key) ShiftFinger() Finger {
+	if k.Finger.Hand() == LeftHand {
+		return RightPinky
+	}
+	return LeftPinky
+}
+
+// Label returns the character printed on a key with or without shift, or
+// false if the position has no character key
+func (l *KeyboardLayout) Label(position KeyPosition, shift bool) (rune, bool) {
+	if position.Row < 0 || position.Row >= len(l.Rows) {
+		return 0, false
+	}
+	row := []rune(l.Rows[position.Row])
+	if shift {
+		row = []rune(l.Shifted[position.Row])
+	}
+	if position.Col < 0 || position.Col >= len(row) {
+		return 0, false
+	}
+	return row[position.Col], true
+}
+
+// RowLength returns the number of character keys in a row
+func RowLength(row int) int {
+	if row < 0 || row >= len(keyFingers) {
+		return 0
+	}
+	return len(keyFingers[row])
+}
+
+// KeyboardLayout maps the characters of a keyboard to keys and fingers
+type KeyboardLayout struct {
+	Name    string
+	Rows    [4]string // Characters of each row without shift, from the number row down
+	Shifted [4]string // The same rows with shift held
+	keys    map[rune]Key
+}
+
+// DefaultKeyboardLayout is the layout used when none is configured
+const DefaultKeyboardLayout = "qwerty"
+
+// NewKeyboardLayout builds a layout from its rows. Each row must have one
+// character per key of a standard keyboard: 13, 13, 11 and 10.
+func NewKeyboardLayout(name string, rows, shifted [4]string) (*KeyboardLayout, error) {
+	layout := &KeyboardLayout{Name: name, Rows: rows, Shifted: shifted, keys: make(map[rune]Key)}
+
+	for row := range rows {
+		for i, chars := range []string{rows[row], shifted[row]} {
+			runes := []rune(chars)
+			if len(runes) != len(keyFingers[row]) {
+				return nil, fmt.Errorf("layout %s: row %d has %d keys, expected %d", name, row+1, len(runes), len(keyFingers[row]))
+			}
+			for col, char := range runes {
+				if _, ok := layout.keys[char]; ok {
+					continue // The first position of a repeated character wins
+				}
+				layout.keys[char] = Key{Row: row, Col: col, Finger: keyFingers[row][col], Shift: i == 1}
+			}
+		}
+	}
+
+	layout.keys[' '] = Key{Row: SpaceRow, Finger: Thumb}
+	layout.keys['\t'] = Key{Row: 1, Col: -1, Finger: LeftPinky}
+	layout.keys[KeyEnter] = Key{Row: 2, Col: len(keyFingers[2]), Finger: RightPinky}
+	return layout, nil
+}
+
+// Key returns where a character is typed, or false if the layout has no
+// key for it
+func (l *KeyboardLayout) Key(char rune) (Key, bool) {
+	key, ok := l.keys[char]
+	return key, ok
+}
+
+// builtinLayouts holds the rows of the built-in layouts
+var builtinLayouts = map[string][2][4]string{
+	"qwerty": {
+		{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"},
+		{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "ZXCVBNM<>?"},
+	},
+	"dvorak": {
+		{"`1234567890[]", "',.pyfgcrl/=\\", "aoeuidhtns-", ";qjkxbmwvz"},
+		{"~!@#$%^&*(){}", "\"<>PYFGCRL?+|", "AOEUIDHTNS_", ":QJKXBMWVZ"},
+	},
+	"colemak": {
+		{"`1234567890-=", "qwfpgjluy;[]\\", "arstdhneio'", "zxcvbkm,./"},
+		{"~!@#$%^&*()_+", "QWFPGJLUY:{}|", "ARSTDHNEIO\"", "ZXCVBKM<>?"},
+	},
+}
+
+// keyboardLayouts maps layout names to their constructors
+var keyboardLayouts = map[string]func() *KeyboardLayout{}
+
+func init() {
+	for name, rows := range builtinLayouts {
+		layout, err := NewKeyboardLayout(name, rows[0], rows[1])
+		if err != nil {
+			panic(err)
+		}
+		RegisterKeyboardLayout(name, func() *KeyboardLayout { return layout })
+	}
+}
+
+// RegisterKeyboardLayout adds or replaces a named keyboard layout
+func RegisterKeyboardLayout(name string, constructor func() *KeyboardLayout) {
+	keyboardLayouts[strings.ToLower(name)] = constructor
+}
+
+// GetKeyboardLayout returns the named keyboard layout
+func GetKeyboardLayout(name string) (*KeyboardLayout, error) {
+	constructor, ok := keyboardLayouts[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown keyboard layout: %s (available: %s)", name, strings.Join(KeyboardLayoutNames(), ", "))
+	}
+	return constructor(), nil
+}
+
+// KeyboardLayoutNames returns all registered layout names in alphabetical order
+func KeyboardLayoutNames() []string {
+	names := make([]string, 0, len(keyboardLayouts))
+	for name := range keyboardLayouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyboardLayoutFile is a user keyboard layout as stored on disk in JSON
+type KeyboardLayoutFile struct {
+	Name    string    `json:"name"`
+	Rows    [4]string `json:"rows"`
+	Shifted [4]string `json:"shifted"`
+}
+
+// LoadKeyboardLayoutFile reads a layout file and registers it under its name
+func LoadKeyboardLayoutFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading keyboard layout: %v", err)
+	}
+
+	var file KeyboardLayoutFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("error parsing keyboard layout %s: %v", path, err)
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	layout, err := NewKeyboardLayout(file.Name, file.Rows, file.Shifted)
+	if err != nil {
+		return "", err
+	}
+	RegisterKeyboardLayout(file.Name, func() *KeyboardLayout { return layout })
+	return file.Name, nil
+}
+
+// LoadKeyboardLayoutDir registers every *.json layout in dir. A missing
+// directory is not an error. Layouts that fail to load are skipped and
+// reported together.
+func LoadKeyboardLayoutDir(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var problems []string
+	for _, path := range paths {
+		name, err := LoadKeyboardLayoutFile(path)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(problems) > 0 {
+		return names, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return names, nil
+}
@@ -1,8 +1,10 @@
 package core
 
 import (
+	"math"
+	"sort"
 	"time"
-	"unicode"
+	"unicode/utf8"
 )
 
 // Metrics handles calculation of typing statistics
@@ -10,41 +12,68 @@ type Metrics struct {
 	totalCharacters   int
 	correctCharacters int
 	mistakes          int
-	totalWords        int
+	totalWords        float64
+	typedCharacters   int // Every character typed, including mistakes and line breaks
+	keystrokes        int // Every key pressed, including backspace attempts
 	startTime         time.Time
+	wpmDefinition     WPMDefinition
+	language          string // Language of the code, for language-aware word counts
 	lines             []LineStats
 	realTimeStats     RealTimeStats
 }
 
 // LineStats stores statistics for individual lines
 type LineStats struct {
-	Original  string
-	UserInput string
-	Mistakes  int
-	Accuracy  float64
-	TimeSpent time.Duration
-	CharCount int
+	Line       int // Index of the line in the practiced file
+	Original   string
+	UserInput  string
+	Mistakes   int
+	Correct    int // Characters typed correctly at their position
+	Accuracy   float64
+	Start      time.Duration // When the line became current, from the session start
+	End        time.Duration // When the line was completed, from the session start
+	Hesitation time.Duration // Time from the start of the line to its first key
+	TimeSpent  time.Duration
+	WPM        float64 // Speed on this line under the session's WPM definition
+	CharCount  int
+	Skipped    bool // Skipped lines are kept for review but not scored
+}
+
+// LineTiming records when a line was typed, as offsets from the start of
+// the session
+type LineTiming struct {
+	Start    time.Duration // The line became the current line
+	FirstKey time.Duration // The first key was pressed on it
+	End      time.Duration // The line was completed
 }
 
 // RealTimeStats stores current typing statistics
 type RealTimeStats struct {
-	WPM         float64
-	CPM         float64
-	Accuracy    float64
-	Mistakes    int
-	ElapsedTime time.Duration
+	WPM           float64
+	WPMDefinition WPMDefinition
+	CPM           float64
+	Accuracy      float64
+	Mistakes      int
+	ElapsedTime   time.Duration
 }
 
 // SessionStats stores final session statistics
 type SessionStats struct {
-	TotalTime       time.Duration
-	WPM             float64
-	CPM             float64
-	Accuracy        float64
-	TotalMistakes   int
-	TotalCharacters int
-	LinesCompleted  int
-	ErrorHeatmap    map[int]int // Position -> mistake count
+	TotalTime         time.Duration
+	WPM               float64
+	WPMDefinition     WPMDefinition // How WPM counts words
+	RawWPM            float64       // Every typed character in standard words, mistakes included
+	NetWPM            float64       // Raw WPM less one word per uncorrected mistake per minute
+	KPM               float64       // Keystrokes per minute
+	Keystrokes        int
+	CPM               float64
+	Accuracy          float64
+	TotalMistakes     int
+	TotalCharacters   int
+	CorrectCharacters int
+	LinesCompleted    int
+	LinesSkipped      int
+	ErrorHeatmap      map[int]int // Position -> mistake count
 }
 
 // NewMetrics creates a new metrics instance
@@ -52,47 +81,117 @@ func NewMetrics() *Metrics {
 	return &Metrics{
 		lines:         make([]LineStats, 0),
 		realTimeStats: RealTimeStats{},
+		wpmDefinition: DefaultWPMDefinition,
 	}
 }
 
+// SetWPMDefinition selects how words are counted for WPM. language is the
+// display name of the code's language, used by WPMLanguage.
+func (m *Metrics) SetWPMDefinition(definition WPMDefinition, language string) {
+	m.wpmDefinition = definition
+	m.language = language
+}
+
+// WPMDefinition returns how words are counted for WPM
+func (m *Metrics) WPMDefinition() WPMDefinition {
+	return m.wpmDefinition
+}
+
+// AddKeystroke counts a key press
+func (m *Metrics) AddKeystroke() {
+	m.keystrokes++
+}
+
 // Reset resets all metrics
 func (m *Metrics) Reset() {
 	m.totalCharacters = 0
 	m.correctCharacters = 0
 	m.mistakes = 0
 	m.totalWords = 0
+	m.typedCharacters = 0
+	m.keystrokes = 0
 	m.lines = make([]LineStats, 0)
 	m.realTimeStats = RealTimeStats{}
 }
 
 // AddLine adds statistics for a completed line
-func (m *Metrics) AddLine(userInput, original string) {
+func (m *Metrics) AddLine(line int, userInput, original string, timing LineTiming) {
+	m.addLine(line, userInput, original, timing, true)
+}
+
+// AddPartialLine adds statistics for a line left unfinished, e.g. when a
+// timed test runs out
+func (m *Metrics) AddPartialLine(line int, userInput, original string, timing LineTiming) {
+	m.addLine(line, userInput, original, timing, false)
+}
+
+// addLine records a line's statistics; completed lines also count the
+// Enter that finished them
+func (m *Metrics) addLine(line int, userInput, original string, timing LineTiming, completed bool) {
 	lineStats := m.calculateLineStats(userInput, original)
+	lineStats.Line = line
+
+	typed := userInput
+	if completed {
+		typed += "\n"
+		original += "\n"
+	}
+	words := m.wpmDefinition.Words(original, m.language)
+
+	lineStats.Start = timing.Start
+	lineStats.End = timing.End
+	lineStats.Hesitation = timing.FirstKey - timing.Start
+	lineStats.TimeSpent = timing.End - timing.Start
+	if minutes := lineStats.TimeSpent.Minutes(); minutes > 0 {
+		lineStats.WPM = words / minutes
+	}
 	m.lines = append(m.lines, lineStats)
 
 	// Update totals
 	m.totalCharacters += lineStats.CharCount
-	m.correctCharacters += (lineStats.CharCount - lineStats.Mistakes)
+	m.correctCharacters += lineStats.Correct
 	m.mistakes += lineStats.Mistakes
-	m.totalWords += m.countWords(original)
+	m.totalWords += words
+	m.typedCharacters += utf8.RuneCountInString(typed)
 }
 
-// calculateLineStats calculates statistics for a single line
+// SkipLine records a line the user skipped after typing userInput. It is
+// left out of the totals.
+func (m *Metrics) SkipLine(line int, userInput, original string, timing LineTiming) {
+	m.lines = append(m.lines, LineStats{
+		Line:       line,
+		Original:   original,
+		UserInput:  userInput,
+		Start:      timing.Start,
+		End:        timing.End,
+		Hesitation: timing.FirstKey - timing.Start,
+		TimeSpent:  timing.End - timing.Start,
+		CharCount:  utf8.RuneCountInString(original),
+		Skipped:    true,
+	})
+}
+
+// calculateLineStats calculates statistics for a single line. Characters
+// are compared as runes, so non-ASCII text counts one per character.
 func (m *Metrics) calculateLineStats(userInput, original string) LineStats {
 	mistakes := 0
-	charCount := len(original)
+	correct := 0
+	input, expected := []rune(userInput), []rune(original)
+	charCount := len(expected)
 
 	// Count character mismatches
-	minLen := min(len(userInput), len(original))
+	minLen := min(len(input), len(expected))
 	for i := 0; i < minLen; i++ {
-		if userInput[i] != original[i] {
+		if input[i] != expected[i] {
 			mistakes++
+		} else {
+			correct++
 		}
 	}
 
 	// Count extra or missing characters
-	if len(userInput) != len(original) {
-		mistakes += abs(len(userInput) - len(original))
+	if len(input) != len(expected) {
+		mistakes += abs(len(input) - len(expected))
 	}
 
 	accuracy := 100.0
@@ -107,11 +206,19 @@ func (m *Metrics) calculateLineStats(userInput, original string) LineStats {
 		Original:  original,
 		UserInput: userInput,
 		Mistakes:  mistakes,
+		Correct:   correct,
 		Accuracy:  accuracy,
 		CharCount: charCount,
 	}
 }
 
+// GetLineStats returns the statistics of every completed line
+func (m *Metrics) GetLineStats() []LineStats {
+	lines := make([]LineStats, len(m.lines))
+	copy(lines, m.lines)
+	return lines
+}
+
 // UpdateRealTime updates real-time statistics during typing
 func (m *Metrics) UpdateRealTime(currentInput, currentLine string, elapsed time.Duration) {
 	if elapsed == 0 {
@@ -119,21 +226,22 @@ func (m *Metrics) UpdateRealTime(currentInput, currentLine string, elapsed time.
 	}
 
 	// Calculate current line mistakes
+	input, expected := []rune(currentInput), []rune(currentLine)
 	currentMistakes := 0
-	minLen := min(len(currentInput), len(currentLine))
+	minLen := min(len(input), len(expected))
 	for i := 0; i < minLen; i++ {
-		if currentInput[i] != currentLine[i] {
+		if input[i] != expected[i] {
 			currentMistakes++
 		}
 	}
 
 	// Add extra characters as mistakes
-	if len(currentInput) > len(currentLine) {
-		currentMistakes += len(currentInput) - len(currentLine)
+	if len(input) > len(expected) {
+		currentMistakes += len(input) - len(expected)
 	}
 
 	// Calculate total characters typed (including current line)
-	totalTyped := m.totalCharacters + len(currentInput)
+	totalTyped := m.totalCharacters + len(input)
 
 	// Calculate total mistakes (including current line)
 	totalMistakes := m.mistakes + currentMistakes
@@ -152,20 +260,74 @@ func (m *Metrics) UpdateRealTime(currentInput, currentLine string, elapsed time.
 	minutes := elapsed.Minutes()
 	if minutes > 0 {
 		// Use total words typed (completed lines + current line words)
-		currentWords := m.countWords(currentInput)
-		totalWords := m.totalWords + currentWords
+		totalWords := m.totalWords + m.wpmDefinition.Words(currentInput, m.language)
 
-		wpm := float64(totalWords) / minutes
+		wpm := totalWords / minutes
 		cpm := float64(totalTyped) / minutes
 
 		m.realTimeStats = RealTimeStats{
-			WPM:         wpm,
-			CPM:         cpm,
-			Accuracy:    accuracy,
-			Mistakes:    totalMistakes,
-			ElapsedTime: elapsed,
+			WPM:           wpm,
+			WPMDefinition: m.wpmDefinition,
+			CPM:           cpm,
+			Accuracy:      accuracy,
+			Mistakes:      totalMistakes,
+			ElapsedTime:   elapsed,
+		}
+	}
+}
+
+// SlowestLines returns up to n lines with the lowest WPM, slowest first.
+// Blank and skipped lines are left out.
+func SlowestLines(lines []LineStats, n int) []LineStats {
+	var typed []LineStats
+	for _, line := range lines {
+		if line.CharCount > 0 && line.TimeSpent > 0 && !line.Skipped {
+			typed = append(typed, line)
 		}
 	}
+
+	sort.SliceStable(typed, func(i, j int) bool {
+		return typed[i].WPM < typed[j].WPM
+	})
+	if len(typed) > n {
+		typed = typed[:n]
+	}
+	return typed
+}
+
+// RedoLines returns the indexes of the lines worth typing again, in the
+// order they were typed: lines typed below 100% accuracy and lines skipped.
+// Blank lines always score 100%, so extra characters on them count too.
+func RedoLines(lines []LineStats) []int {
+	var redo []int
+	for _, line := range lines {
+		if line.Skipped || line.Accuracy < 100 || line.Mistakes > 0 {
+			redo = append(redo, line.Line)
+		}
+	}
+	return redo
+}
+
+// ErrorProneLines returns up to n lines with the most mistakes, worst
+// first. Lines typed without mistakes are left out.
+func ErrorProneLines(lines []LineStats, n int) []LineStats {
+	var mistaken []LineStats
+	for _, line := range lines {
+		if line.Mistakes > 0 {
+			mistaken = append(mistaken, line)
+		}
+	}
+
+	sort.SliceStable(mistaken, func(i, j int) bool {
+		if mistaken[i].Mistakes != mistaken[j].Mistakes {
+			return mistaken[i].Mistakes > mistaken[j].Mistakes
+		}
+		return mistaken[i].Accuracy < mistaken[j].Accuracy
+	})
+	if len(mistaken) > n {
+		mistaken = mistaken[:n]
+	}
+	return mistaken
 }
 
 // GetCurrentStats returns current real-time statistics
@@ -187,15 +349,22 @@ func (m *Metrics) GetSessionStats(totalTime time.Duration) SessionStats {
 		}
 	}
 
-	var wpm, cpm float64
-	if totalTime.Minutes() > 0 {
-		wpm = float64(m.totalWords) / totalTime.Minutes()
-		cpm = float64(totalChars) / totalTime.Minutes()
+	var wpm, cpm, rawWPM, netWPM, kpm float64
+	if minutes := totalTime.Minutes(); minutes > 0 {
+		wpm = m.totalWords / minutes
+		cpm = float64(totalChars) / minutes
+		rawWPM = StandardWPM(m.typedCharacters, totalTime)
+		netWPM = math.Max(0, rawWPM-float64(totalMistakes)/minutes)
+		kpm = float64(m.keystrokes) / minutes
 	}
 
 	// Generate error heatmap
 	heatmap := make(map[int]int)
+	skipped := 0
 	for _, line := range m.lines {
+		if line.Skipped {
+			skipped++
+		}
 		// This is simplified - in a full implementation,
 		// you'd track position-specific errors
 		if line.Mistakes > 0 {
@@ -204,46 +373,34 @@ func (m *Metrics) GetSessionStats(totalTime time.Duration) SessionStats {
 	}
 
 	return SessionStats{
-		TotalTime:       totalTime,
-		WPM:             wpm,
-		CPM:             cpm,
-		Accuracy:        accuracy,
-		TotalMistakes:   totalMistakes,
-		TotalCharacters: totalChars,
-		LinesCompleted:  len(m.lines),
-		ErrorHeatmap:    heatmap,
+		TotalTime:         totalTime,
+		WPM:               wpm,
+		WPMDefinition:     m.wpmDefinition,
+		RawWPM:            rawWPM,
+		NetWPM:            netWPM,
+		KPM:               kpm,
+		Keystrokes:        m.keystrokes,
+		CPM:               cpm,
+		Accuracy:          accuracy,
+		TotalMistakes:     totalMistakes,
+		TotalCharacters:   totalChars,
+		CorrectCharacters: m.correctCharacters,
+		LinesCompleted:    len(m.lines) - skipped,
+		LinesSkipped:      skipped,
+		ErrorHeatmap:      heatmap,
 	}
 }
 
-// countWords counts the number of words in a string
-func (m *Metrics) countWords(text string) int {
-	if len(text) == 0 {
+// StandardWPM converts characters typed in elapsed time to words per
+// minute, counting five characters as a word
+func StandardWPM(characters int, elapsed time.Duration) float64 {
+	if elapsed <= 0 || characters <= 0 {
 		return 0
 	}
-
-	words := 0
-	inWord := false
-
-	for _, char := range text {
-		if unicode.IsSpace(char) {
-			inWord = false
-		} else if !inWord {
-			words++
-			inWord = true
-		}
-	}
-
-	return words
+	return float64(characters) / charsPerWord / elapsed.Minutes()
 }
 
 // Helper functions
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 func abs(x int) int {
 	if x < 0 {
 		return -x
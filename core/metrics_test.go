@@ -0,0 +1,44 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedoLines(t *testing.T) {
+	lines := []string{"a := 1", "", "b := 2", "}"}
+	const skip = "<skip>" // Skips the line instead of typing it
+
+	tests := []struct {
+		name   string
+		inputs []string // What is typed on each line before Enter
+		want   []int
+	}{
+		{"clean run", []string{"a := 1", "", "b := 2", "}"}, nil},
+		{"mistake", []string{"a := 1", "", "b := 3", "}"}, []int{2}},
+		{"line ended early", []string{"a :=", "", "b := 2", "}"}, []int{0}},
+		{"extra characters on a blank line", []string{"a := 1", "x", "b := 2", "}"}, []int{1}},
+		{"skipped line", []string{"a := 1", "", "b := 2", skip}, []int{3}},
+		{"in the order typed", []string{"a := 2", "", skip, "]"}, []int{0, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := NewSession(lines)
+			for _, input := range tt.inputs {
+				if input == skip {
+					session.SkipLine()
+					continue
+				}
+				for _, char := range input {
+					session.TypeRune(char)
+				}
+				session.CompleteLine()
+			}
+
+			if got := RedoLines(session.LineStats()); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RedoLines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
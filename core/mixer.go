@@ -0,0 +1,103 @@
+package core
+
+import (
+	"math"
+	"sync"
+)
+
+// defaultMaxVoices is how many sounds the mixer plays at once
+const defaultMaxVoices = 8
+
+// voice is one sound being played by the mixer
+type voice struct {
+	samples []int16
+	pos     int
+}
+
+// Mixer combines overlapping sounds into a single stream of 16-bit little
+// endian stereo PCM. It never runs dry: when nothing is playing it produces
+// silence, so one long-lived player can read from it for the whole session.
+type Mixer struct {
+	mu        sync.Mutex
+	voices    []*voice
+	maxVoices int
+}
+
+// NewMixer creates a mixer that plays at most maxVoices sounds at once
+func NewMixer(maxVoices int) *Mixer {
+	if maxVoices < 1 {
+		maxVoices = 1
+	}
+	return &Mixer{maxVoices: maxVoices}
+}
+
+// Add starts playing samples. When every voice is busy the oldest sound is
+// cut off to make room, so bursts of events never pile up.
+func (m *Mixer) Add(samples []int16) {
+	if len(samples) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.voices) >= m.maxVoices {
+		m.voices = m.voices[len(m.voices)-m.maxVoices+1:]
+	}
+	m.voices = append(m.voices, &voice{samples: samples})
+}
+
+// Clear stops every sound
+func (m *Mixer) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.voices = nil
+}
+
+// Active returns how many sounds are playing
+func (m *Mixer) Active() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.voices)
+}
+
+// Read fills p with the mix of all playing sounds, or silence
+func (m *Mixer) Read(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Only whole samples are written
+	n := len(p) / audioBitDepth * audioBitDepth
+	for i := 0; i < n; i += audioBitDepth {
+		sum := 0
+		for _, v := range m.voices {
+			if v.pos < len(v.samples) {
+				sum += int(v.samples[v.pos])
+				v.pos++
+			}
+		}
+
+		sample := int16(sum)
+		if sum > math.MaxInt16 {
+			sample = math.MaxInt16
+		} else if sum < math.MinInt16 {
+			sample = math.MinInt16
+		}
+		p[i] = byte(sample & 0xff)
+		p[i+1] = byte((sample >> 8) & 0xff)
+	}
+
+	// Drop finished voices
+	active := m.voices[:0]
+	for _, v := range m.voices {
+		if v.pos < len(v.samples) {
+			active = append(active, v)
+		}
+	}
+	for i := len(active); i < len(m.voices); i++ {
+		m.voices[i] = nil
+	}
+	m.voices = active
+
+	return n, nil
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// readSamples reads n samples from the mixer
+func readSamples(t *testing.T, m *Mixer, n int) []int16 {
+	t.Helper()
+	buf := make([]byte, n*audioBitDepth)
+	read, err := m.Read(buf)
+	if err != nil || read != len(buf) {
+		t.Fatalf("Read() = %d, %v, want %d bytes", read, err, len(buf))
+	}
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(buf[i*audioBitDepth:]))
+	}
+	return samples
+}
+
+func TestMixerRead(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxVoices int
+		sounds    [][]int16
+		read      int
+		want      []int16
+		active    int // Sounds still playing after the read
+	}{
+		{"silence", 8, nil, 3, []int16{0, 0, 0}, 0},
+		{"one sound then silence", 8, [][]int16{{1, 2}}, 4, []int16{1, 2, 0, 0}, 0},
+		{"sound longer than the read", 8, [][]int16{{1, 2, 3}}, 2, []int16{1, 2}, 1},
+		{"overlapping sounds add up", 8, [][]int16{{100, 100, 100}, {-30, 20}}, 3, []int16{70, 120, 100}, 0},
+		{"clipped high", 8, [][]int16{{30000}, {30000}}, 1, []int16{math.MaxInt16}, 0},
+		{"clipped low", 8, [][]int16{{-30000}, {-30000}}, 1, []int16{math.MinInt16}, 0},
+		{"oldest sound cut off", 2, [][]int16{{1}, {10}, {100}}, 1, []int16{110}, 0},
+		{"empty sounds are ignored", 1, [][]int16{{5}, {}}, 1, []int16{5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMixer(tt.maxVoices)
+			for _, sound := range tt.sounds {
+				m.Add(sound)
+			}
+			if got := readSamples(t, m, tt.read); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("samples = %v, want %v", got, tt.want)
+			}
+			if got := m.Active(); got != tt.active {
+				t.Errorf("active = %d, want %d", got, tt.active)
+			}
+		})
+	}
+}
+
+func TestMixerReadWholeSamples(t *testing.T) {
+	m := NewMixer(8)
+	m.Add([]int16{1, 2})
+
+	buf := make([]byte, 3)
+	if n, err := m.Read(buf); n != 2 || err != nil {
+		t.Fatalf("Read() into 3 bytes = %d, %v, want 2 bytes", n, err)
+	}
+	if got := readSamples(t, m, 1); got[0] != 2 {
+		t.Errorf("next sample = %d, want 2", got[0])
+	}
+}
@@ -16,6 +16,7 @@ type KeystrokeEvent struct {
 // MusclePowerIndicator tracks typing stamina and power
 type MusclePowerIndicator struct {
 	keystrokes      []KeystrokeEvent
+	clock           Clock
 	sessionStart    time.Time
 	lastKeystroke   time.Time
 	windowSize      time.Duration // sliding window for calculations
@@ -25,6 +26,7 @@ type MusclePowerIndicator struct {
 	currentPower     float64
 	peakPower        float64
 	powerHistory     []PowerSnapshot
+	snapshotInterval time.Duration // minimum time between power snapshots
 	consistencyScore float64
 	staminaLevel     float64
 	fatigueDetected  bool
@@ -45,6 +47,13 @@ type PowerSnapshot struct {
 	Status    PowerStatus
 }
 
+// maxPowerHistory is the number of snapshots kept before the history is
+// compacted to half its resolution
+const maxPowerHistory = 200
+
+// defaultSnapshotInterval is the initial spacing between power snapshots
+const defaultSnapshotInterval = time.Second
+
 // PowerStatus represents different power states
 type PowerStatus int
 
@@ -68,20 +77,28 @@ type PowerLevel struct {
 // NewMusclePowerIndicator creates a new MPI tracker
 func NewMusclePowerIndicator() *MusclePowerIndicator {
 	return &MusclePowerIndicator{
-		keystrokes:      make([]KeystrokeEvent, 0),
-		sessionStart:    time.Now(),
-		windowSize:      30 * time.Second, // 30-second sliding window
-		maxWindowEvents: 1000,             // Keep last 1000 keystrokes max
-		powerHistory:    make([]PowerSnapshot, 0),
-		currentPower:    1.0,
-		peakPower:       1.0,
-		staminaLevel:    1.0,
+		keystrokes:       make([]KeystrokeEvent, 0),
+		clock:            time.Now,
+		sessionStart:     time.Now(),
+		windowSize:       30 * time.Second, // 30-second sliding window
+		maxWindowEvents:  1000,             // Keep last 1000 keystrokes max
+		powerHistory:     make([]PowerSnapshot, 0),
+		snapshotInterval: defaultSnapshotInterval,
+		currentPower:     1.0,
+		peakPower:        1.0,
+		staminaLevel:     1.0,
 	}
 }
 
+// SetClock replaces the time source and restarts the session on it
+func (mpi *MusclePowerIndicator) SetClock(clock Clock) {
+	mpi.clock = clock
+	mpi.sessionStart = clock()
+}
+
 // RecordKeystroke adds a new keystroke event
 func (mpi *MusclePowerIndicator) RecordKeystroke(char rune, isCorrect bool, isBackspace bool) {
-	now := time.Now()
+	now := mpi.clock()
 
 	event := KeystrokeEvent{
 		Character:   char,
@@ -119,7 +136,7 @@ func (mpi *MusclePowerIndicator) RecordKeystroke(char rune, isCorrect bool, isBa
 
 // cleanOldEvents removes events outside the sliding window
 func (mpi *MusclePowerIndicator) cleanOldEvents() {
-	cutoff := time.Now().Add(-mpi.windowSize)
+	cutoff := mpi.clock().Add(-mpi.windowSize)
 
 	// Remove events older than window
 	filtered := make([]KeystrokeEvent, 0)
@@ -161,7 +178,7 @@ func (mpi *MusclePowerIndicator) calculatePower() {
 		return
 	}
 
-	now := time.Now()
+	now := mpi.clock()
 	windowStart := now.Add(-mpi.windowSize)
 
 	var correctCount, incorrectCount, backspaceCount int
@@ -197,7 +214,7 @@ func (mpi *MusclePowerIndicator) calculatePower() {
 	}
 
 	// Calculate base metrics
-	elapsedSeconds := time.Since(mpi.sessionStart).Seconds()
+	elapsedSeconds := now.Sub(mpi.sessionStart).Seconds()
 	if elapsedSeconds < 1 {
 		elapsedSeconds = 1
 	}
@@ -251,16 +268,24 @@ func (mpi *MusclePowerIndicator) calculatePower() {
 	// Update consistency score
 	mpi.consistencyScore = consistencyBonus
 
-	// Record power snapshot
-	mpi.powerHistory = append(mpi.powerHistory, PowerSnapshot{
-		Timestamp: now,
-		Power:     mpi.currentPower,
-		Status:    mpi.getCurrentStatus(),
-	})
+	// Record a power snapshot at most once per interval
+	if len(mpi.powerHistory) == 0 || now.Sub(mpi.powerHistory[len(mpi.powerHistory)-1].Timestamp) >= mpi.snapshotInterval {
+		mpi.powerHistory = append(mpi.powerHistory, PowerSnapshot{
+			Timestamp: now,
+			Power:     mpi.currentPower,
+			Status:    mpi.getCurrentStatus(),
+		})
+	}
 
-	// Limit history size
-	if len(mpi.powerHistory) > 100 {
-		mpi.powerHistory = mpi.powerHistory[1:]
+	// When the history is full, keep every other snapshot and double the
+	// interval so the history always spans the whole session
+	if len(mpi.powerHistory) > maxPowerHistory {
+		compacted := make([]PowerSnapshot, 0, maxPowerHistory/2+1)
+		for i := 0; i < len(mpi.powerHistory); i += 2 {
+			compacted = append(compacted, mpi.powerHistory[i])
+		}
+		mpi.powerHistory = compacted
+		mpi.snapshotInterval *= 2
 	}
 }
 
@@ -365,7 +390,7 @@ func (mpi *MusclePowerIndicator) GetCurrentPowerLevel() PowerLevel {
 
 // GetStats returns detailed MPI statistics
 func (mpi *MusclePowerIndicator) GetStats() map[string]interface{} {
-	elapsedSeconds := time.Since(mpi.sessionStart).Seconds()
+	elapsedSeconds := mpi.clock().Sub(mpi.sessionStart).Seconds()
 
 	return map[string]interface{}{
 		"current_power":       mpi.currentPower,
@@ -381,12 +406,25 @@ func (mpi *MusclePowerIndicator) GetStats() map[string]interface{} {
 	}
 }
 
+// GetPowerHistory returns the power snapshots recorded this session, oldest first
+func (mpi *MusclePowerIndicator) GetPowerHistory() []PowerSnapshot {
+	history := make([]PowerSnapshot, len(mpi.powerHistory))
+	copy(history, mpi.powerHistory)
+	return history
+}
+
+// SessionStart returns when the current MPI session began
+func (mpi *MusclePowerIndicator) SessionStart() time.Time {
+	return mpi.sessionStart
+}
+
 // Reset resets the MPI for a new session
 func (mpi *MusclePowerIndicator) Reset() {
 	mpi.keystrokes = make([]KeystrokeEvent, 0)
-	mpi.sessionStart = time.Now()
+	mpi.sessionStart = mpi.clock()
 	mpi.lastKeystroke = time.Time{}
 	mpi.powerHistory = make([]PowerSnapshot, 0)
+	mpi.snapshotInterval = defaultSnapshotInterval
 	mpi.currentPower = 1.0
 	mpi.peakPower = 1.0
 	mpi.consistencyScore = 1.0
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// charsPerWord is the standard word length used to convert WPM to characters
+const charsPerWord = 5
+
+// Pacer compares typing progress against a target speed
+type Pacer struct {
+	targetWPM float64
+}
+
+// NewPacer creates a pacer for the target words per minute
+func NewPacer(targetWPM float64) *Pacer {
+	return &Pacer{targetWPM: targetWPM}
+}
+
+// TargetWPM returns the target speed
+func (p *Pacer) TargetWPM() float64 {
+	return p.targetWPM
+}
+
+// CharInterval returns the time per character at the target speed
+func (p *Pacer) CharInterval() time.Duration {
+	return time.Duration(float64(time.Minute) / (p.targetWPM * charsPerWord))
+}
+
+// BeatInterval returns the time per word at the target speed, the
+// metronome's tempo
+func (p *Pacer) BeatInterval() time.Duration {
+	return time.Duration(float64(time.Minute) / p.targetWPM)
+}
+
+// ExpectedChars returns how many characters the target pace has typed after elapsed
+func (p *Pacer) ExpectedChars(elapsed time.Duration) int {
+	return int(elapsed / p.CharInterval())
+}
+
+// Offset returns how far ahead (positive) or behind (negative) the target
+// pace typedChars is after elapsed, as time
+func (p *Pacer) Offset(typedChars int, elapsed time.Duration) time.Duration {
+	ahead := float64(typedChars) - float64(elapsed)/float64(p.CharInterval())
+	return time.Duration(ahead * float64(p.CharInterval()))
+}
+
+// Position returns the line and column (within the line's typed text) the
+// target pace has reached after elapsed, or -1, -1 once it has passed the
+// last line. lines holds what is typed of each line, e.g. from
+// Session.TypedLines.
+func (p *Pacer) Position(lines []string, elapsed time.Duration) (int, int) {
+	chars := p.ExpectedChars(elapsed)
+	for i, line := range lines {
+		length := utf8.RuneCountInString(line)
+		if chars < length {
+			return i, chars
+		}
+		chars -= length
+	}
+	return -1, -1
+}
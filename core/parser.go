@@ -67,6 +67,37 @@ func (p *Parser) ParseFile(filename string) (string, error) {
 	return strings.Join(lines, "\n"), nil
 }
 
+// languageNames maps supported file extensions to display names
+var languageNames = map[string]string{
+	".go":   "Go",
+	".py":   "Python",
+	".js":   "JavaScript",
+	".cpp":  "C++",
+	".c":    "C",
+	".java": "Java",
+	".rs":   "Rust",
+	".ts":   "TypeScript",
+	".jsx":  "JavaScript",
+	".tsx":  "TypeScript",
+}
+
+// LanguageForFile returns the language name for a file based on its extension
+func LanguageForFile(filename string) string {
+	if name, ok := languageNames[strings.ToLower(filepath.Ext(filename))]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// LanguageFromName resolves a language name or shortcut such as "go", "py"
+// or "cpp" to its display name
+func LanguageFromName(name string) string {
+	if language, ok := languageNames["."+strings.ToLower(name)]; ok {
+		return language
+	}
+	return name
+}
+
 // IsSupported checks if a file extension is supported
 func (p *Parser) IsSupported(filename string) bool {
 	ext := filepath.Ext(filename)
@@ -0,0 +1,164 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Playlist is a set of practice entries typed back to back
+type Playlist struct {
+	Name    string          `yaml:"name"`
+	Entries []PlaylistEntry `yaml:"entries"`
+
+	path string
+}
+
+// PlaylistEntry is one file, or part of one, in a playlist, with the
+// settings it is practiced with
+type PlaylistEntry struct {
+	File   string `yaml:"file"`             // Path relative to the playlist, or a sample such as "go"
+	Symbol string `yaml:"symbol,omitempty"` // Function, method, type or class to type
+	Lines  string `yaml:"lines,omitempty"`  // Line range to type, such as "10-40"
+	Title  string `yaml:"title,omitempty"`
+
+	Pace     int   `yaml:"pace,omitempty"`     // Target WPM for the metronome
+	Keyboard *bool `yaml:"keyboard,omitempty"` // Show the on-screen keyboard
+	Repeat   int   `yaml:"repeat,omitempty"`   // Times to type the entry in a row
+
+	path      string // Resolved file path
+	lineRange LineRange
+}
+
+// LoadPlaylist reads and checks a playlist file. Entry files are resolved
+// relative to the playlist's folder.
+func LoadPlaylist(path string) (*Playlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading playlist: %v", err)
+	}
+
+	playlist := &Playlist{path: path}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(playlist); err != nil {
+		return nil, fmt.Errorf("error parsing playlist %s: %v", path, err)
+	}
+
+	if len(playlist.Entries) == 0 {
+		return nil, fmt.Errorf("playlist %s has no entries", path)
+	}
+	if playlist.Name == "" {
+		playlist.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	for i := range playlist.Entries {
+		if err := playlist.Entries[i].resolve(filepath.Dir(path)); err != nil {
+			return nil, fmt.Errorf("playlist entry %d: %v", i+1, err)
+		}
+	}
+	return playlist, nil
+}
+
+// Path returns the file the playlist was loaded from
+func (p *Playlist) Path() string {
+	return p.path
+}
+
+// Steps returns the entries in the order they are typed, with repeated
+// entries listed once per repeat
+func (p *Playlist) Steps() []PlaylistEntry {
+	var steps []PlaylistEntry
+	for _, entry := range p.Entries {
+		for i := 0; i < max(entry.Repeat, 1); i++ {
+			steps = append(steps, entry)
+		}
+	}
+	return steps
+}
+
+// resolve checks an entry's settings and finds its file
+func (e *PlaylistEntry) resolve(dir string) error {
+	switch {
+	case e.File == "":
+		return fmt.Errorf("missing file")
+	case e.Symbol != "" && e.Lines != "":
+		return fmt.Errorf("use either symbol or lines, not both")
+	case e.Pace < 0 || e.Pace > 300:
+		return fmt.Errorf("invalid pace %d (use 1 to 300)", e.Pace)
+	case e.Repeat < 0:
+		return fmt.Errorf("invalid repeat %d", e.Repeat)
+	}
+
+	if e.Lines != "" {
+		lineRange, err := ParseLineRange(e.Lines)
+		if err != nil {
+			return err
+		}
+		e.lineRange = lineRange
+	}
+
+	switch {
+	case e.File == "~" || strings.HasPrefix(e.File, "~/") || filepath.IsAbs(e.File):
+		e.path = ExpandFilePath(e.File)
+	default:
+		if _, ok := FindSample(e.File); ok {
+			e.path = ExpandFilePath(e.File)
+		} else {
+			e.path = filepath.Join(dir, e.File)
+		}
+	}
+	return nil
+}
+
+// Path returns the entry's resolved file path
+func (e PlaylistEntry) Path() string {
+	return e.path
+}
+
+// Partial reports whether the entry types only part of its file
+func (e PlaylistEntry) Partial() bool {
+	return e.Symbol != "" || e.Lines != ""
+}
+
+// Name returns the entry's title, or a description of what it types
+func (e PlaylistEntry) Name() string {
+	switch {
+	case e.Title != "":
+		return e.Title
+	case e.Symbol != "":
+		return filepath.Base(e.path) + " › " + e.Symbol
+	case e.Lines != "":
+		return filepath.Base(e.path) + ":" + e.lineRange.String()
+	}
+	return filepath.Base(e.path)
+}
+
+// Load reads the entry's file and returns the code to type
+func (e PlaylistEntry) Load(parser *Parser) (string, error) {
+	content, err := parser.ParseFile(e.path)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(content, "\n")
+	switch {
+	case e.Symbol != "":
+		lines, err = ExtractSymbol(lines, LanguageForFile(e.path), e.Symbol)
+	case e.Lines != "":
+		lines, err = e.lineRange.Extract(lines)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", filepath.Base(e.path), err)
+	}
+
+	content = strings.Join(lines, "\n")
+	if strings.TrimSpace(content) == "" {
+		return "", fmt.Errorf("%s: nothing to type", e.Name())
+	}
+	return content, nil
+}
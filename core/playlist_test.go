@@ -0,0 +1,63 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadPlaylist(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string // Part of the error, "" for a valid playlist
+	}{
+		{"valid", "entries:\n  - file: main.go\n    lines: 10-\n  - file: go\n    symbol: main\n    pace: 60\n    repeat: 2\n", ""},
+		{"no entries", "name: empty\nentries: []\n", "has no entries"},
+		{"unknown key", "entries:\n  - file: main.go\n    speed: 60\n", "field speed not found"},
+		{"missing file", "entries:\n  - lines: 1-5\n", "entry 1: missing file"},
+		{"symbol and lines", "entries:\n  - file: main.go\n  - file: main.go\n    symbol: main\n    lines: 1-5\n", "entry 2: use either symbol or lines"},
+		{"pace too high", "entries:\n  - file: main.go\n    pace: 500\n", "invalid pace 500"},
+		{"negative pace", "entries:\n  - file: main.go\n    pace: -1\n", "invalid pace -1"},
+		{"negative repeat", "entries:\n  - file: main.go\n    repeat: -2\n", "invalid repeat -2"},
+		{"bad line range", "entries:\n  - file: main.go\n    lines: 40-10\n", "invalid line range"},
+		{"not yaml", "entries: [", "error parsing playlist"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "warmup.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			playlist, err := LoadPlaylist(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadPlaylist() = %v, want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadPlaylist() returned error: %v", err)
+			}
+
+			if playlist.Name != "warmup" {
+				t.Errorf("name = %q, want the file name %q", playlist.Name, "warmup")
+			}
+			if got, want := playlist.Entries[0].Path(), filepath.Join(filepath.Dir(path), "main.go"); got != want {
+				t.Errorf("entry 1 path = %q, want %q next to the playlist", got, want)
+			}
+			if got := playlist.Entries[0].Name(); got != "main.go:10-" {
+				t.Errorf("entry 1 name = %q, want %q", got, "main.go:10-")
+			}
+			if got, want := playlist.Entries[1].Path(), ExpandFilePath("go"); got != want {
+				t.Errorf("entry 2 path = %q, want the sample %q", got, want)
+			}
+			if got := len(playlist.Steps()); got != 3 {
+				t.Errorf("steps = %d, want 3", got)
+			}
+		})
+	}
+}
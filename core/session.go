@@ -0,0 +1,616 @@
+package core
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// EventType identifies what happened in a typing session
+type EventType int
+
+const (
+	EventKeyTyped        EventType = iota // A character or backspace was typed
+	EventLineCompleted                    // The user finished the current line
+	EventSessionFinished                  // Every line has been completed
+)
+
+// Event describes a change in a typing session. Only the fields relevant to
+// the event type are set.
+type Event struct {
+	Type EventType
+	Line int // Index of the line the event belongs to
+
+	// KeyTyped
+	Char       rune
+	Correct    bool
+	Backspace  bool
+	NewMistake bool // First mistake at this position, used for error feedback
+
+	// LineCompleted
+	Input    string
+	Expected string
+	Perfect  bool
+	Skipped  bool // The line was skipped instead of typed
+
+	// SessionFinished
+	Stats SessionStats
+}
+
+// EventHandler receives session events
+type EventHandler func(Event)
+
+// Session is a UI-independent typing session over a list of code lines.
+// Front ends feed it keystrokes and render its state.
+type Session struct {
+	lines       []string
+	currentLine int
+	startLine   int // Lines before it are left untyped after a restart
+	difficulty  Difficulty
+	language    string
+
+	// Typing state
+	userInput      string
+	lastMistakePos int            // Track last mistake position to avoid repeated feedback
+	completedLines map[int]string // Maps line number to user's typed input
+	lineStart      time.Duration  // When the current line became current
+	lineFirstKey   time.Duration  // First key on the current line, -1 before it
+	keystrokes     []Keystroke    // Every key pressed, for latency analysis
+	pastes         int            // Text pasted instead of typed
+
+	// Metrics
+	metrics *Metrics
+	timer   *Timer
+	mpi     *MusclePowerIndicator
+
+	timeLimit  time.Duration // Timed tests end when it runs out; 0 for no limit
+	finished   bool
+	finalStats SessionStats
+	handlers   []EventHandler
+}
+
+// NewSession creates a session for the given code lines
+func NewSession(lines []string) *Session {
+	s := &Session{
+		lines:      lines,
+		difficulty: DifficultyNormal,
+		metrics:    NewMetrics(),
+		timer:      NewTimer(),
+		mpi:        NewMusclePowerIndicator(),
+	}
+	s.Reset()
+	return s
+}
+
+// OnEvent registers a handler that is called for every session event
+func (s *Session) OnEvent(handler EventHandler) {
+	s.handlers = append(s.handlers, handler)
+}
+
+// emit delivers an event to all registered handlers
+func (s *Session) emit(event Event) {
+	for _, handler := range s.handlers {
+		handler(event)
+	}
+}
+
+// SetClock replaces the time source used for timing and power tracking
+func (s *Session) SetClock(clock Clock) {
+	s.timer.SetClock(clock)
+	s.mpi.SetClock(clock)
+}
+
+// SetWPMDefinition selects how words are counted for WPM. language is the
+// display name of the code's language, e.g. from LanguageForFile.
+func (s *Session) SetWPMDefinition(definition WPMDefinition, language string) {
+	s.language = language
+	s.metrics.SetWPMDefinition(definition, language)
+}
+
+// WPMDefinition returns how words are counted for WPM
+func (s *Session) WPMDefinition() WPMDefinition {
+	return s.metrics.WPMDefinition()
+}
+
+// SetDifficulty selects how much of each line is typed and restarts the
+// session from the current line
+func (s *Session) SetDifficulty(difficulty Difficulty) {
+	s.difficulty = difficulty
+	s.RestartFrom(s.currentLine)
+}
+
+// Difficulty returns how much of each line is typed
+func (s *Session) Difficulty() Difficulty {
+	return s.difficulty
+}
+
+// SetLines replaces the code being practiced and resets the session
+func (s *Session) SetLines(lines []string) {
+	s.lines = lines
+	s.Reset()
+}
+
+// AppendLines adds code to the end of the session, e.g. to keep a timed
+// test supplied with text
+func (s *Session) AppendLines(lines ...string) {
+	s.lines = append(s.lines, lines...)
+}
+
+// SetTimeLimit turns the session into a timed test that finishes when the
+// limit is reached instead of after the last line. Zero removes the limit.
+func (s *Session) SetTimeLimit(limit time.Duration) {
+	s.timeLimit = limit
+}
+
+// TimeLimit returns the timed test length, or 0 for a normal session
+func (s *Session) TimeLimit() time.Duration {
+	return s.timeLimit
+}
+
+// Remaining returns the time left in a timed test
+func (s *Session) Remaining() time.Duration {
+	if s.timeLimit == 0 {
+		return 0
+	}
+	return s.timeLimit - s.Elapsed()
+}
+
+// CheckTimeLimit finishes a timed test whose time has run out and reports
+// whether the session is over
+func (s *Session) CheckTimeLimit() bool {
+	if s.finished {
+		return true
+	}
+	if s.timeLimit > 0 && s.timer.IsRunning() && s.timer.Elapsed() >= s.timeLimit {
+		s.finish()
+		return true
+	}
+	return false
+}
+
+// Reset returns the session to the first line with fresh metrics
+func (s *Session) Reset() {
+	s.RestartFrom(0)
+}
+
+// RestartFrom starts the session over at a line with fresh metrics. The
+// lines before it are left untyped.
+func (s *Session) RestartFrom(line int) {
+	line = min(line, len(s.lines)-1)
+	line = max(line, 0)
+	s.startLine = line
+	s.currentLine = line
+	s.userInput = ""
+	s.lastMistakePos = -1
+	s.completedLines = make(map[int]string)
+	s.lineStart = 0
+	s.lineFirstKey = -1
+	s.keystrokes = nil
+	s.pastes = 0
+	s.finished = false
+	s.finalStats = SessionStats{}
+	s.timer.Reset()
+	s.metrics.Reset()
+	s.mpi.Reset()
+
+	// Start on a line that is typed, but never past the last one
+	for s.currentLine < len(s.lines)-1 && s.LeftOut(s.currentLine) {
+		s.currentLine++
+	}
+}
+
+// Start starts the session timer
+func (s *Session) Start() {
+	s.timer.Start()
+}
+
+// Stop pauses timing without discarding progress
+func (s *Session) Stop() {
+	s.timer.Stop()
+}
+
+// TypeRune records a typed character against the current line
+func (s *Session) TypeRune(char rune) {
+	if s.CheckTimeLimit() {
+		return
+	}
+
+	s.metrics.AddKeystroke()
+	oldInputLen := utf8.RuneCountInString(s.userInput)
+	s.userInput += string(char)
+
+	// Check if this character is a mistake; positions count characters,
+	// not bytes
+	currentLine := []rune(s.CurrentLine())
+	isCorrect := false
+	newMistake := false
+	var expected rune
+
+	if oldInputLen < len(currentLine) {
+		expected = currentLine[oldInputLen]
+		isCorrect = expected == char
+	}
+
+	// Typing beyond the line length is also a mistake; only the first
+	// mistake at a position is reported as new
+	if !isCorrect && s.lastMistakePos != oldInputLen {
+		newMistake = true
+		s.lastMistakePos = oldInputLen
+	}
+
+	s.mpi.RecordKeystroke(char, isCorrect, false)
+
+	// Start timer on first keypress
+	if !s.timer.IsRunning() {
+		s.timer.Start()
+	}
+	s.markKey()
+	s.recordKey(char, expected, isCorrect)
+
+	s.UpdateMetrics()
+
+	s.emit(Event{
+		Type:       EventKeyTyped,
+		Line:       s.currentLine,
+		Char:       char,
+		Correct:    isCorrect,
+		NewMistake: newMistake,
+	})
+}
+
+// Backspace records a backspace attempt. Corrections are not allowed, so
+// the input is left untouched and the attempt counts against the MPI.
+func (s *Session) Backspace() {
+	if s.CheckTimeLimit() {
+		return
+	}
+
+	s.metrics.AddKeystroke()
+	s.mpi.RecordKeystroke(0, false, true)
+	if s.timer.IsRunning() {
+		s.markKey()
+	}
+	s.recordKey(KeyBackspace, 0, false)
+
+	s.emit(Event{
+		Type:      EventKeyTyped,
+		Line:      s.currentLine,
+		Backspace: true,
+	})
+}
+
+// Paste records text pasted into the session. Pasted text is not typed;
+// pastes are counted in the keystroke log so the session fails validation.
+func (s *Session) Paste() {
+	if s.finished {
+		return
+	}
+	s.pastes++
+}
+
+// Pastes returns how many times text was pasted into the session
+func (s *Session) Pastes() int {
+	return s.pastes
+}
+
+// CompleteLine submits the current input and advances to the next line
+func (s *Session) CompleteLine() {
+	if s.CheckTimeLimit() {
+		return
+	}
+
+	s.metrics.AddKeystroke()
+	line := s.currentLine
+	currentCode := s.CurrentLine()
+	input := s.userInput
+	codeRunes, inputRunes := []rune(currentCode), []rune(input)
+
+	// Store the user's input for this completed line
+	s.completedLines[line] = input
+
+	// Start timer when Enter is the first keypress
+	if !s.timer.IsRunning() {
+		s.timer.Start()
+	}
+
+	// Calculate accuracy and timing for this line
+	end := s.timer.Elapsed()
+	s.markKey()
+	expected := rune(KeyEnter)
+	if len(inputRunes) < len(codeRunes) {
+		expected = codeRunes[len(inputRunes)]
+	}
+	s.recordKey(KeyEnter, expected, len(inputRunes) == len(codeRunes))
+	s.metrics.AddLine(line, input, currentCode, s.lineTiming(end))
+
+	// Move to next line
+	s.currentLine++
+	s.userInput = ""
+	s.lastMistakePos = -1
+	s.lineStart = end
+	s.lineFirstKey = -1
+
+	s.emit(Event{
+		Type:     EventLineCompleted,
+		Line:     line,
+		Input:    input,
+		Expected: currentCode,
+		Perfect:  input == currentCode,
+	})
+
+	s.nextLine()
+}
+
+// SkipLine moves on to the next line without typing the rest of the
+// current one. The line is recorded as skipped and is not scored.
+func (s *Session) SkipLine() {
+	if s.CheckTimeLimit() || s.currentLine >= len(s.lines) {
+		return
+	}
+
+	line := s.currentLine
+	currentCode := s.CurrentLine()
+	end := s.timer.Elapsed()
+	if s.timer.IsRunning() {
+		s.markKey()
+	}
+	s.recordKey(KeySkip, 0, true)
+	s.metrics.SkipLine(line, s.userInput, currentCode, s.lineTiming(end))
+
+	s.currentLine++
+	s.userInput = ""
+	s.lastMistakePos = -1
+	s.lineStart = end
+	s.lineFirstKey = -1
+
+	s.emit(Event{
+		Type:     EventLineCompleted,
+		Line:     line,
+		Expected: currentCode,
+		Skipped:  true,
+	})
+
+	s.nextLine()
+}
+
+// nextLine moves past lines the difficulty leaves out and finishes the
+// session after the last line
+func (s *Session) nextLine() {
+	for s.currentLine < len(s.lines) && s.LeftOut(s.currentLine) {
+		s.currentLine++
+	}
+	if s.currentLine >= len(s.lines) {
+		s.finish()
+	}
+}
+
+// markKey notes the time of the first key pressed on the current line
+func (s *Session) markKey() {
+	if s.lineFirstKey < 0 {
+		s.lineFirstKey = s.timer.Elapsed()
+	}
+}
+
+// recordKey adds a key press to the keystroke stream
+func (s *Session) recordKey(char, expected rune, correct bool) {
+	key := Keystroke{Char: char, Time: s.timer.Elapsed(), Correct: correct}
+	if !correct {
+		key.Expected = expected
+	}
+	s.keystrokes = append(s.keystrokes, key)
+}
+
+// lineTiming returns the timing of the current line ending at end
+func (s *Session) lineTiming(end time.Duration) LineTiming {
+	firstKey := s.lineFirstKey
+	if firstKey < 0 {
+		firstKey = end
+	}
+	return LineTiming{Start: s.lineStart, FirstKey: firstKey, End: end}
+}
+
+// finish stops the session and calculates final statistics
+func (s *Session) finish() {
+	s.timer.Stop()
+	s.finished = true
+
+	// A timed test scores the part of the current line typed before time ran out
+	if s.timeLimit > 0 && s.userInput != "" {
+		expected := s.CurrentLine()
+		if typed := utf8.RuneCountInString(s.userInput); utf8.RuneCountInString(expected) > typed {
+			expected = string([]rune(expected)[:typed])
+		}
+		s.metrics.AddPartialLine(s.currentLine, s.userInput, expected, s.lineTiming(s.Elapsed()))
+	}
+
+	s.finalStats = s.metrics.GetSessionStats(s.Elapsed())
+
+	s.emit(Event{
+		Type:  EventSessionFinished,
+		Line:  s.currentLine,
+		Stats: s.finalStats,
+	})
+}
+
+// UpdateMetrics refreshes real-time statistics for the running session
+func (s *Session) UpdateMetrics() {
+	if s.timer.IsRunning() {
+		s.metrics.UpdateRealTime(s.userInput, s.CurrentLine(), s.timer.Elapsed())
+	}
+}
+
+// Lines returns the code lines being practiced
+func (s *Session) Lines() []string {
+	return s.lines
+}
+
+// LineCount returns the number of lines in the session
+func (s *Session) LineCount() int {
+	return len(s.lines)
+}
+
+// CurrentLineIndex returns the index of the line being typed
+func (s *Session) CurrentLineIndex() int {
+	return s.currentLine
+}
+
+// CurrentLine returns the part of the current line that is typed
+func (s *Session) CurrentLine() string {
+	return s.difficulty.TypedText(s.CurrentLineRaw())
+}
+
+// LineText returns the part of a line that is typed at the session's
+// difficulty
+func (s *Session) LineText(line int) string {
+	if line < 0 || line >= len(s.lines) {
+		return ""
+	}
+	return s.difficulty.TypedText(s.lines[line])
+}
+
+// LeftOut reports whether a line is left out by the difficulty
+func (s *Session) LeftOut(line int) bool {
+	return line >= 0 && line < len(s.lines) && s.difficulty.Skips(s.lines[line], s.language)
+}
+
+// TypedLines returns what is typed of every line, with lines that are not
+// typed in this session left empty
+func (s *Session) TypedLines() []string {
+	typed := make([]string, len(s.lines))
+	for i := s.startLine; i < len(s.lines); i++ {
+		if !s.LeftOut(i) {
+			typed[i] = s.LineText(i)
+		}
+	}
+	return typed
+}
+
+// StartLine returns the line the session started at
+func (s *Session) StartLine() int {
+	return s.startLine
+}
+
+// CurrentLineRaw returns the current line with original whitespace
+func (s *Session) CurrentLineRaw() string {
+	if s.currentLine >= len(s.lines) {
+		return ""
+	}
+	return s.lines[s.currentLine]
+}
+
+// Input returns what the user has typed on the current line
+func (s *Session) Input() string {
+	return s.userInput
+}
+
+// NextChar returns the character the current line expects next, or
+// KeyEnter once the line has been typed
+func (s *Session) NextChar() rune {
+	line := []rune(s.CurrentLine())
+	if typed := utf8.RuneCountInString(s.userInput); typed < len(line) {
+		return line[typed]
+	}
+	return KeyEnter
+}
+
+// CompletedInput returns what the user typed for a completed line
+func (s *Session) CompletedInput(line int) (string, bool) {
+	input, ok := s.completedLines[line]
+	return input, ok
+}
+
+// TypedCharacters returns how far through the text the user is, counting
+// each completed line's full length plus the current input
+func (s *Session) TypedCharacters() int {
+	typed := utf8.RuneCountInString(s.userInput)
+	for i, line := range s.TypedLines() {
+		if i >= s.currentLine {
+			break
+		}
+		typed += utf8.RuneCountInString(line)
+	}
+	return typed
+}
+
+// Keystrokes returns every key pressed in the session, in order
+func (s *Session) Keystrokes() []Keystroke {
+	keystrokes := make([]Keystroke, len(s.keystrokes))
+	copy(keystrokes, s.keystrokes)
+	return keystrokes
+}
+
+// KeyLog returns the keystroke stream packed for storage, with what is
+// needed to replay it. A session with pastes but no keys still has a log,
+// so the pastes are kept.
+func (s *Session) KeyLog() *KeyLog {
+	log := NewKeyLog(s.keystrokes)
+	if log == nil {
+		if s.pastes == 0 {
+			return nil
+		}
+		log = &KeyLog{}
+	}
+	for _, line := range s.metrics.GetLineStats() {
+		log.LineLengths = append(log.LineLengths, line.CharCount)
+	}
+	log.Pastes = s.pastes
+	return log
+}
+
+// LineStats returns the statistics of every completed line in the order
+// they were typed
+func (s *Session) LineStats() []LineStats {
+	return s.metrics.GetLineStats()
+}
+
+// IsRunning returns true while the session timer is running
+func (s *Session) IsRunning() bool {
+	return s.timer.IsRunning()
+}
+
+// Elapsed returns the time spent in the session so far, which never
+// exceeds a timed test's limit
+func (s *Session) Elapsed() time.Duration {
+	elapsed := s.timer.Elapsed()
+	if s.timeLimit > 0 && elapsed > s.timeLimit {
+		return s.timeLimit
+	}
+	return elapsed
+}
+
+// IsFinished reports whether every line has been completed
+func (s *Session) IsFinished() bool {
+	return s.finished
+}
+
+// CurrentStats returns real-time statistics for the running session
+func (s *Session) CurrentStats() RealTimeStats {
+	return s.metrics.GetCurrentStats()
+}
+
+// Stats returns the final statistics, or a snapshot if still in progress
+func (s *Session) Stats() SessionStats {
+	if s.finished {
+		return s.finalStats
+	}
+	return s.metrics.GetSessionStats(s.Elapsed())
+}
+
+// StandardWPM returns the speed in standard words of five characters,
+// counting correctly typed characters and the line break of every line
+// typed without mistakes. Unlike Stats().WPM it does not depend on how many
+// tokens the code has, so it compares fairly across languages.
+func (s *Session) StandardWPM() float64 {
+	stats := s.Stats()
+	characters := stats.CorrectCharacters
+	for line, input := range s.completedLines {
+		if input == s.LineText(line) {
+			characters++
+		}
+	}
+	return StandardWPM(characters, stats.TotalTime)
+}
+
+// MPI returns the session's muscle power indicator
+func (s *Session) MPI() *MusclePowerIndicator {
+	return s.mpi
+}
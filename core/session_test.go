@@ -0,0 +1,28 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompleteLineStartsTimer(t *testing.T) {
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := NewSession([]string{"", "x := 1"})
+	session.SetClock(func() time.Time { return now })
+	session.SetTimeLimit(30 * time.Second)
+
+	// Enter on the blank first line is the first keypress of the test
+	session.CompleteLine()
+	if !session.IsRunning() {
+		t.Fatal("timer not running after the first Enter")
+	}
+
+	now = now.Add(31 * time.Second)
+	session.TypeRune('x')
+	if !session.IsFinished() {
+		t.Errorf("session not finished %s after the first Enter of a 30s test", 31*time.Second)
+	}
+	if got := session.Elapsed(); got != 30*time.Second {
+		t.Errorf("elapsed = %s, want 30s", got)
+	}
+}
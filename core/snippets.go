@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// SnippetStream deals the blocks of a source file in random order, without
+// end, for timed tests. Every snippet is used once before any repeats.
+type SnippetStream struct {
+	snippets [][]string
+	order    []int
+	next     int
+	rng      *rand.Rand
+}
+
+// NewSnippetStream splits content into snippets at blank lines
+func NewSnippetStream(content string, seed int64) (*SnippetStream, error) {
+	snippets := splitSnippets(content)
+	if len(snippets) == 0 {
+		return nil, fmt.Errorf("no code to build a test from")
+	}
+
+	return &SnippetStream{
+		snippets: snippets,
+		rng:      rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// splitSnippets groups non-blank lines into blocks separated by blank lines
+func splitSnippets(content string) [][]string {
+	var snippets [][]string
+	var current []string
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if len(current) > 0 {
+				snippets = append(snippets, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		snippets = append(snippets, current)
+	}
+	return snippets
+}
+
+// Next returns the next snippet, reshuffling once all have been dealt
+func (s *SnippetStream) Next() []string {
+	if s.next >= len(s.order) {
+		s.order = s.rng.Perm(len(s.snippets))
+		s.next = 0
+	}
+	snippet := s.snippets[s.order[s.next]]
+	s.next++
+	return snippet
+}
+
+// Lines returns whole snippets until at least count lines are collected
+func (s *SnippetStream) Lines(count int) []string {
+	var lines []string
+	for len(lines) < count {
+		lines = append(lines, s.Next()...)
+	}
+	return lines
+}
@@ -0,0 +1,316 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SoundEvent identifies a moment in a session that can play a sound
+type SoundEvent string
+
+const (
+	SoundKeypress        SoundEvent = "keypress"
+	SoundError           SoundEvent = "error"
+	SoundLineComplete    SoundEvent = "line_complete"
+	SoundAchievement     SoundEvent = "achievement"
+	SoundSessionComplete SoundEvent = "session_complete"
+	SoundMetronome       SoundEvent = "metronome"
+)
+
+// SoundEvents lists every event a sound theme can define
+var SoundEvents = []SoundEvent{
+	SoundKeypress,
+	SoundError,
+	SoundLineComplete,
+	SoundAchievement,
+	SoundSessionComplete,
+	SoundMetronome,
+}
+
+// DefaultSoundTheme is the sound theme used when none is configured
+const DefaultSoundTheme = "classic"
+
+// Tone is one synthesized note of a sound
+type Tone struct {
+	Wave       string  `json:"wave,omitempty"` // "sine" (default) or "click"
+	Frequency  float64 `json:"frequency,omitempty"`
+	DurationMS int     `json:"duration_ms"`
+}
+
+// Sound describes what plays for one event: a sequence of synthesized
+// tones or a WAV file. A sound with neither is silent.
+type Sound struct {
+	Tones  []Tone `json:"tones,omitempty"`
+	File   string `json:"file,omitempty"`
+	Volume *int   `json:"volume,omitempty"` // Percent, 100 if unset
+}
+
+// SoundTheme is a named set of sounds for session events
+type SoundTheme struct {
+	Name   string
+	Sounds map[SoundEvent]Sound
+
+	dir string // Directory relative WAV paths are resolved against
+}
+
+// sine returns a sine tone
+func sine(frequency float64, ms int) Tone {
+	return Tone{Wave: "sine", Frequency: frequency, DurationMS: ms}
+}
+
+// classicSounds are the original error beep and success chime plus
+// matching achievement and session-complete fanfares
+func classicSounds() map[SoundEvent]Sound {
+	return map[SoundEvent]Sound{
+		SoundError:           {Tones: []Tone{sine(800, 100)}},
+		SoundLineComplete:    {Tones: []Tone{sine(523, 80), sine(659, 80)}},
+		SoundAchievement:     {Tones: []Tone{sine(523, 70), sine(659, 70), sine(784, 120)}},
+		SoundSessionComplete: {Tones: []Tone{sine(523, 100), sine(659, 100), sine(784, 100), sine(1047, 200)}},
+		SoundMetronome:       metronomeTick(),
+	}
+}
+
+// metronomeTick is the pacing tick shared by the built-in themes
+func metronomeTick() Sound {
+	return Sound{Tones: []Tone{{Wave: "click", Frequency: 1500, DurationMS: 25}}}
+}
+
+// soundThemes maps sound theme names to their constructors
+var soundThemes = map[string]func() *SoundTheme{
+	"classic": func() *SoundTheme {
+		return &SoundTheme{Name: "classic", Sounds: classicSounds()}
+	},
+	"mechanical": func() *SoundTheme {
+		sounds := classicSounds()
+		volume := 60
+		sounds[SoundKeypress] = Sound{Tones: []Tone{{Wave: "click", Frequency: 2400, DurationMS: 18}}, Volume: &volume}
+		return &SoundTheme{Name: "mechanical", Sounds: sounds}
+	},
+	"minimal": func() *SoundTheme {
+		return &SoundTheme{Name: "minimal", Sounds: map[SoundEvent]Sound{
+			SoundError:     {Tones: []Tone{sine(800, 100)}},
+			SoundMetronome: metronomeTick(),
+		}}
+	},
+}
+
+// RegisterSoundTheme adds or replaces a named sound theme
+func RegisterSoundTheme(name string, constructor func() *SoundTheme) {
+	soundThemes[strings.ToLower(name)] = constructor
+}
+
+// GetSoundTheme returns a new instance of the named sound theme
+func GetSoundTheme(name string) (*SoundTheme, error) {
+	constructor, ok := soundThemes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown sound theme: %s (available: %s)", name, strings.Join(SoundThemeNames(), ", "))
+	}
+	return constructor(), nil
+}
+
+// SoundThemeNames returns all registered sound theme names in alphabetical order
+func SoundThemeNames() []string {
+	names := make([]string, 0, len(soundThemes))
+	for name := range soundThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isSoundEvent reports whether event is a known sound event
+func isSoundEvent(event SoundEvent) bool {
+	for _, e := range SoundEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Render produces the samples for a sound at its own volume, in the
+// playback format
+func (t *SoundTheme) Render(event SoundEvent) ([]int16, error) {
+	sound, ok := t.Sounds[event]
+	if !ok {
+		return nil, nil
+	}
+
+	var samples []int16
+	if sound.File != "" {
+		path := sound.File
+		if !filepath.IsAbs(path) && t.dir != "" {
+			path = filepath.Join(t.dir, path)
+		}
+		wav, err := LoadWAV(path)
+		if err != nil {
+			return nil, err
+		}
+		samples = wav
+	} else {
+		for _, tone := range sound.Tones {
+			samples = append(samples, synthesize(tone)...)
+		}
+	}
+
+	if sound.Volume != nil {
+		samples = scaleSamples(samples, float64(*sound.Volume)/100)
+	}
+	return samples, nil
+}
+
+// synthesize renders a tone as interleaved stereo samples
+func synthesize(tone Tone) []int16 {
+	duration := time.Duration(tone.DurationMS) * time.Millisecond
+	frames := int(float64(audioSampleRate) * duration.Seconds())
+	samples := make([]int16, frames*audioChannels)
+
+	// A fixed seed keeps click sounds identical between runs
+	noise := uint32(2463534242)
+	fadeFrames := int(0.01 * float64(audioSampleRate)) // 10ms fade
+
+	for i := 0; i < frames; i++ {
+		t := float64(i) / float64(audioSampleRate)
+		var sample float64
+
+		switch tone.Wave {
+		case "click":
+			// A mechanical key: a burst of noise with a short metallic ping,
+			// both decaying quickly
+			noise ^= noise << 13
+			noise ^= noise >> 17
+			noise ^= noise << 5
+			white := float64(noise)/float64(math.MaxUint32)*2 - 1
+			decay := math.Exp(-t / (duration.Seconds() / 5))
+			sample = (0.7*white + 0.3*math.Sin(2*math.Pi*tone.Frequency*t)) * decay
+		default:
+			sample = math.Sin(2 * math.Pi * tone.Frequency * t)
+
+			// Apply envelope to avoid clicks (fade in/out)
+			if i < fadeFrames {
+				sample *= float64(i) / float64(fadeFrames)
+			} else if i > frames-fadeFrames {
+				sample *= float64(frames-i) / float64(fadeFrames)
+			}
+		}
+
+		pcm := int16(sample * 0.3 * 32767) // Reduce volume to 30%
+		for ch := 0; ch < audioChannels; ch++ {
+			samples[i*audioChannels+ch] = pcm
+		}
+	}
+	return samples
+}
+
+// scaleSamples returns a copy of samples at the given volume
+func scaleSamples(samples []int16, volume float64) []int16 {
+	scaled := make([]int16, len(samples))
+	for i, sample := range samples {
+		scaled[i] = clampSample(float64(sample) * volume)
+	}
+	return scaled
+}
+
+// clampSample converts a value to a 16-bit sample, clipping out-of-range values
+func clampSample(value float64) int16 {
+	if value > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if value < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(value)
+}
+
+// SoundThemeFile is a user sound theme as stored on disk in JSON
+type SoundThemeFile struct {
+	Name   string               `json:"name"`
+	Base   string               `json:"base"`
+	Sounds map[SoundEvent]Sound `json:"sounds"`
+}
+
+// LoadSoundThemeFile reads a sound theme file and registers it under its
+// name. Sounds in the file replace those of the base theme; WAV paths are
+// relative to the file.
+func LoadSoundThemeFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading sound theme: %v", err)
+	}
+
+	var file SoundThemeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("error parsing sound theme %s: %v", path, err)
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	for event := range file.Sounds {
+		if !isSoundEvent(event) {
+			return "", fmt.Errorf("sound theme %s: unknown event %q", file.Name, event)
+		}
+	}
+
+	base := file.Base
+	if base == "" {
+		base = DefaultSoundTheme
+	}
+	baseConstructor, ok := soundThemes[strings.ToLower(base)]
+	if !ok {
+		return "", fmt.Errorf("sound theme %s: unknown base %q", file.Name, base)
+	}
+
+	dir := filepath.Dir(path)
+	constructor := func() *SoundTheme {
+		t := baseConstructor()
+		t.Name = file.Name
+		t.dir = dir
+		for event, sound := range file.Sounds {
+			t.Sounds[event] = sound
+		}
+		return t
+	}
+
+	// Render once now so missing or broken WAV files are reported on load
+	t := constructor()
+	for event := range file.Sounds {
+		if _, err := t.Render(event); err != nil {
+			return "", fmt.Errorf("sound theme %s: %v", file.Name, err)
+		}
+	}
+
+	RegisterSoundTheme(file.Name, constructor)
+	return file.Name, nil
+}
+
+// LoadSoundThemeDir registers every *.json sound theme in dir. A missing
+// directory is not an error. Themes that fail to load are skipped and
+// reported together.
+func LoadSoundThemeDir(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var problems []string
+	for _, path := range paths {
+		name, err := LoadSoundThemeFile(path)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(problems) > 0 {
+		return names, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return names, nil
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TeamEntry is a user's best result in one ranking
+type TeamEntry struct {
+	User     string
+	Best     BundleResult
+	Attempts int
+}
+
+// TeamRanking is the best result of each user in one group of results,
+// fastest first
+type TeamRanking struct {
+	Title   string
+	Entries []TeamEntry
+}
+
+// TeamRankings ranks merged results by timed test length, by snippet and
+// by language
+type TeamRankings struct {
+	Users     []string
+	Durations []TeamRanking // Timed tests of each length
+	Snippets  []TeamRanking // Practice sessions of each snippet, by its content
+	Languages []TeamRanking // Every result in each language
+}
+
+// RankTeam merges the results typed at a difficulty into rankings. A
+// user's results from several bundles are combined, and results exported
+// more than once count once. An empty language matches every language.
+func RankTeam(bundles []*ResultBundle, language string, difficulty Difficulty) TeamRankings {
+	language = LanguageFromName(language)
+
+	type key struct {
+		user      string
+		timestamp int64
+	}
+	seen := make(map[key]bool)
+	users := make(map[string]bool)
+	durations := make(map[string]map[string]*TeamEntry)
+	snippets := make(map[string]map[string]*TeamEntry)
+	snippetNames := make(map[string]string)
+	languages := make(map[string]map[string]*TeamEntry)
+
+	for _, bundle := range bundles {
+		for _, result := range bundle.Results {
+			k := key{bundle.User, result.Timestamp.UnixNano()}
+			if seen[k] || result.Level() != difficulty || (language != "" && !strings.EqualFold(result.Language, language)) {
+				continue
+			}
+			seen[k] = true
+			users[bundle.User] = true
+
+			if result.Mode == ModeTest {
+				addTeamResult(durations, strconv.Itoa(result.TimeLimitS), bundle.User, result)
+			} else {
+				group := snippetGroup(result)
+				snippetNames[group] = result.Snippet
+				addTeamResult(snippets, group, bundle.User, result)
+			}
+			addTeamResult(languages, result.Language, bundle.User, result)
+		}
+	}
+
+	var rankings TeamRankings
+	for user := range users {
+		rankings.Users = append(rankings.Users, user)
+	}
+	sort.Strings(rankings.Users)
+
+	for _, d := range TestDurations {
+		if entries, ok := durations[strconv.Itoa(int(d.Seconds()))]; ok {
+			rankings.Durations = append(rankings.Durations, newTeamRanking(fmt.Sprintf("%ds tests", int(d.Seconds())), entries))
+		}
+	}
+	// Files that share a name but not their code are told apart by hash
+	nameCount := make(map[string]int)
+	for _, name := range snippetNames {
+		nameCount[name]++
+	}
+	for group, entries := range snippets {
+		title := snippetNames[group]
+		if nameCount[title] > 1 {
+			if strings.HasPrefix(group, "name:") {
+				title += " (older results)"
+			} else {
+				title = fmt.Sprintf("%s (%s)", title, group[:min(8, len(group))])
+			}
+		}
+		rankings.Snippets = append(rankings.Snippets, newTeamRanking(title, entries))
+	}
+	for name, entries := range languages {
+		rankings.Languages = append(rankings.Languages, newTeamRanking(name, entries))
+	}
+
+	// Snippets most of the team has typed come first
+	sort.Slice(rankings.Snippets, func(i, j int) bool {
+		a, b := rankings.Snippets[i], rankings.Snippets[j]
+		if len(a.Entries) != len(b.Entries) {
+			return len(a.Entries) > len(b.Entries)
+		}
+		return a.Title < b.Title
+	})
+	sort.Slice(rankings.Languages, func(i, j int) bool {
+		return rankings.Languages[i].Title < rankings.Languages[j].Title
+	})
+	return rankings
+}
+
+// snippetGroup returns the group a practice result is ranked in: the hash
+// of its lines, or its file name for results exported before hashes were
+// recorded
+func snippetGroup(result BundleResult) string {
+	if result.SnippetHash != "" {
+		return result.SnippetHash
+	}
+	return "name:" + result.Snippet
+}
+
+// addTeamResult keeps a user's best result in a group
+func addTeamResult(groups map[string]map[string]*TeamEntry, group, user string, result BundleResult) {
+	if groups[group] == nil {
+		groups[group] = make(map[string]*TeamEntry)
+	}
+	entry := groups[group][user]
+	if entry == nil {
+		entry = &TeamEntry{User: user, Best: result}
+		groups[group][user] = entry
+	}
+	entry.Attempts++
+	if betterResult(result, entry.Best) {
+		entry.Best = result
+	}
+}
+
+// betterResult reports whether a is faster than b, with accuracy breaking ties
+func betterResult(a, b BundleResult) bool {
+	if a.WPM != b.WPM {
+		return a.WPM > b.WPM
+	}
+	return a.Accuracy > b.Accuracy
+}
+
+// newTeamRanking sorts the users' best results in a group
+func newTeamRanking(title string, entries map[string]*TeamEntry) TeamRanking {
+	ranking := TeamRanking{Title: title}
+	for _, entry := range entries {
+		ranking.Entries = append(ranking.Entries, *entry)
+	}
+	sort.Slice(ranking.Entries, func(i, j int) bool {
+		a, b := ranking.Entries[i], ranking.Entries[j]
+		if a.Best.WPM != b.Best.WPM || a.Best.Accuracy != b.Best.Accuracy {
+			return betterResult(a.Best, b.Best)
+		}
+		return a.User < b.User
+	})
+	return ranking
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// rankingTitles returns the title of each ranking and how many users it lists
+func rankingTitles(rankings []TeamRanking) map[string]int {
+	titles := make(map[string]int)
+	for _, ranking := range rankings {
+		titles[ranking.Title] = len(ranking.Entries)
+	}
+	return titles
+}
+
+func TestRankTeam(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	hashA, hashB := ContentHash([]string{"a := 1"}), ContentHash([]string{"b := 2"})
+
+	// result returns a practice result typed at minute n
+	result := func(n int, snippet, hash string, wpm float64) BundleResult {
+		return BundleResult{Timestamp: start.Add(time.Duration(n) * time.Minute), Snippet: snippet, SnippetHash: hash, Language: "Go", WPM: wpm}
+	}
+	bundle := func(user string, results ...BundleResult) *ResultBundle {
+		return &ResultBundle{User: user, Results: results}
+	}
+
+	hard := result(4, "main.go", hashA, 90)
+	hard.Difficulty = DifficultyHard
+	test := BundleResult{Timestamp: start, Language: "Go", Mode: ModeTest, TimeLimitS: 30, WPM: 70}
+	python := result(5, "main.py", "", 40)
+	python.Language = "Python"
+
+	tests := []struct {
+		name       string
+		bundles    []*ResultBundle
+		language   string
+		difficulty Difficulty
+		users      []string
+		durations  map[string]int
+		snippets   map[string]int
+	}{
+		{
+			name: "same code ranks together",
+			bundles: []*ResultBundle{
+				bundle("ann", result(1, "main.go", hashA, 50)),
+				bundle("bob", result(2, "main.go", hashA, 60)),
+			},
+			difficulty: DifficultyNormal,
+			users:      []string{"ann", "bob"},
+			durations:  map[string]int{},
+			snippets:   map[string]int{"main.go": 2},
+		},
+		{
+			name: "same name with different code ranks apart",
+			bundles: []*ResultBundle{
+				bundle("ann", result(1, "main.go", hashA, 50)),
+				bundle("bob", result(2, "main.go", hashB, 60), result(3, "main.go", "", 30)),
+			},
+			difficulty: DifficultyNormal,
+			users:      []string{"ann", "bob"},
+			durations:  map[string]int{},
+			snippets: map[string]int{
+				"main.go (" + hashA[:8] + ")": 1,
+				"main.go (" + hashB[:8] + ")": 1,
+				"main.go (older results)":     1,
+			},
+		},
+		{
+			name: "short hash does not panic",
+			bundles: []*ResultBundle{
+				bundle("ann", result(1, "main.go", "ab", 50)),
+				bundle("bob", result(2, "main.go", hashA, 60)),
+			},
+			difficulty: DifficultyNormal,
+			users:      []string{"ann", "bob"},
+			durations:  map[string]int{},
+			snippets:   map[string]int{"main.go (ab)": 1, "main.go (" + hashA[:8] + ")": 1},
+		},
+		{
+			name: "results exported twice count once",
+			bundles: []*ResultBundle{
+				bundle("ann", result(1, "main.go", hashA, 50)),
+				bundle("ann", result(1, "main.go", hashA, 50), test),
+			},
+			difficulty: DifficultyNormal,
+			users:      []string{"ann"},
+			durations:  map[string]int{"30s tests": 1},
+			snippets:   map[string]int{"main.go": 1},
+		},
+		{
+			name:       "other difficulties are left out",
+			bundles:    []*ResultBundle{bundle("ann", result(1, "main.go", hashA, 50)), bundle("bob", hard)},
+			difficulty: DifficultyHard,
+			users:      []string{"bob"},
+			durations:  map[string]int{},
+			snippets:   map[string]int{"main.go": 1},
+		},
+		{
+			name:       "language filter",
+			bundles:    []*ResultBundle{bundle("ann", result(1, "main.go", hashA, 50), python)},
+			language:   "py",
+			difficulty: DifficultyNormal,
+			users:      []string{"ann"},
+			durations:  map[string]int{},
+			snippets:   map[string]int{"main.py": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rankings := RankTeam(tt.bundles, tt.language, tt.difficulty)
+			if !reflect.DeepEqual(rankings.Users, tt.users) {
+				t.Errorf("users = %v, want %v", rankings.Users, tt.users)
+			}
+			if got := rankingTitles(rankings.Durations); !reflect.DeepEqual(got, tt.durations) {
+				t.Errorf("durations = %v, want %v", got, tt.durations)
+			}
+			if got := rankingTitles(rankings.Snippets); !reflect.DeepEqual(got, tt.snippets) {
+				t.Errorf("snippets = %v, want %v", got, tt.snippets)
+			}
+		})
+	}
+}
+
+func TestRankTeamKeepsBestResult(t *testing.T) {
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	results := []BundleResult{
+		{Timestamp: start, Snippet: "main.go", Language: "Go", WPM: 40, Accuracy: 99},
+		{Timestamp: start.Add(time.Minute), Snippet: "main.go", Language: "Go", WPM: 55, Accuracy: 90},
+		{Timestamp: start.Add(2 * time.Minute), Snippet: "main.go", Language: "Go", WPM: 55, Accuracy: 95},
+	}
+	rankings := RankTeam([]*ResultBundle{{User: "ann", Results: results}}, "", DifficultyNormal)
+
+	if len(rankings.Snippets) != 1 || len(rankings.Snippets[0].Entries) != 1 {
+		t.Fatalf("snippets = %+v, want one entry", rankings.Snippets)
+	}
+	entry := rankings.Snippets[0].Entries[0]
+	if entry.Attempts != 3 || entry.Best.WPM != 55 || entry.Best.Accuracy != 95 {
+		t.Errorf("entry = %d attempts, best %.0f WPM %.0f%%, want 3 attempts, best 55 WPM 95%%", entry.Attempts, entry.Best.WPM, entry.Best.Accuracy)
+	}
+}
@@ -4,21 +4,31 @@ import (
 	"time"
 )
 
+// Clock returns the current time. It defaults to time.Now and can be
+// replaced to drive sessions on a simulated timeline.
+type Clock func() time.Time
+
 // Timer handles timing functionality for typing sessions
 type Timer struct {
 	startTime time.Time
 	endTime   time.Time
 	running   bool
+	clock     Clock
 }
 
 // NewTimer creates a new timer instance
 func NewTimer() *Timer {
-	return &Timer{}
+	return &Timer{clock: time.Now}
+}
+
+// SetClock replaces the time source used by the timer
+func (t *Timer) SetClock(clock Clock) {
+	t.clock = clock
 }
 
 // Start starts the timer
 func (t *Timer) Start() {
-	t.startTime = time.Now()
+	t.startTime = t.clock()
 	t.running = true
 	t.endTime = time.Time{} // Reset end time
 }
@@ -26,7 +36,7 @@ func (t *Timer) Start() {
 // Stop stops the timer
 func (t *Timer) Stop() {
 	if t.running {
-		t.endTime = time.Now()
+		t.endTime = t.clock()
 		t.running = false
 	}
 }
@@ -50,7 +60,7 @@ func (t *Timer) Elapsed() time.Duration {
 	}
 
 	if t.running {
-		return time.Since(t.startTime)
+		return t.clock().Sub(t.startTime)
 	}
 
 	if !t.endTime.IsZero() {
@@ -71,5 +81,5 @@ func (t *Timer) TotalTime() time.Duration {
 	}
 
 	// If still running, return current elapsed time
-	return time.Since(t.startTime)
+	return t.clock().Sub(t.startTime)
 }
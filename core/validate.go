@@ -0,0 +1,338 @@
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Limits on how fast and how evenly people type. Sessions that break them
+// were pasted or typed by a program.
+const (
+	MinKeyInterval = 10 * time.Millisecond // Keys this close only come together now and then, when they overlap
+	MaxBurstWPM    = 300.0                 // Fastest speed held over burstKeys keys, in standard words
+
+	maxFastKeys        = 0.05 // Share of keys allowed to come within MinKeyInterval
+	burstKeys          = 30   // Keys a burst is measured over
+	uniformKeys        = 50   // Gaps needed before their spread is judged
+	minTimingVariation = 0.1  // Lowest spread of the gaps between keys, relative to their mean
+)
+
+// ErrUnverifiable is returned for results recorded before sessions kept
+// what is needed to verify them
+var ErrUnverifiable = errors.New("recorded without the keystrokes needed to verify it")
+
+// SessionClaim is what a result reports about its session
+type SessionClaim struct {
+	Duration   time.Duration
+	TimeLimit  time.Duration // 0 for practice sessions
+	Characters int
+	Mistakes   int
+	Accuracy   float64
+	WPM        float64 // In standard five-character words
+}
+
+// Validate checks the record against its keystroke log
+func (r SessionRecord) Validate() error {
+	wpm, _ := r.WPMAs(WPMStandard)
+	return ValidateKeystrokes(r.Keystrokes, SessionClaim{
+		Duration:   r.Duration(),
+		TimeLimit:  r.TimeLimit(),
+		Characters: r.Characters,
+		Mistakes:   r.Mistakes,
+		Accuracy:   r.Accuracy,
+		WPM:        wpm,
+	})
+}
+
+// Validate checks the result against its keystroke log. A snippet hash
+// that is not a SHA-256 is rejected, since rankings are grouped by it.
+func (r BundleResult) Validate() error {
+	if r.SnippetHash != "" && !validContentHash(r.SnippetHash) {
+		return fmt.Errorf("snippet hash %q is not a SHA-256", r.SnippetHash)
+	}
+	return ValidateKeystrokes(r.Keystrokes, SessionClaim{
+		Duration:   time.Duration(r.DurationMS) * time.Millisecond,
+		TimeLimit:  r.TimeLimit(),
+		Characters: r.Characters,
+		Mistakes:   r.Mistakes,
+		Accuracy:   r.Accuracy,
+		WPM:        r.WPM,
+	})
+}
+
+// validContentHash reports whether hash looks like one from ContentHash:
+// 64 lowercase hex characters
+func validContentHash(hash string) bool {
+	if len(hash) != 2*sha256.Size {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateKeystrokes checks that a keystroke log was typed by hand and that
+// replaying it through the metrics engine gives the claimed results. Every
+// problem found is reported.
+func ValidateKeystrokes(log *KeyLog, claim SessionClaim) error {
+	if log == nil || (len(log.DelaysMS) == 0 && log.Pastes == 0) {
+		return ErrUnverifiable
+	}
+
+	var problems []string
+	if log.Pastes == 1 {
+		problems = append(problems, "text was pasted")
+	} else if log.Pastes > 1 {
+		problems = append(problems, fmt.Sprintf("text was pasted %d times", log.Pastes))
+	}
+
+	// A session of only pastes has no keys to check
+	var err error
+	if len(log.DelaysMS) > 0 {
+		problems = append(problems, checkKeyTiming(log.DelaysMS)...)
+		err = checkReplay(log, claim)
+		if err != nil && err != ErrUnverifiable {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return err
+}
+
+// checkKeyTiming looks for keys that follow each other too quickly, bursts
+// faster than anyone types, and gaps too even to come from a person
+func checkKeyTiming(delays []int64) []string {
+	if len(delays) < 2 {
+		return nil
+	}
+	// The first delay is from the start of the session, not from a key
+	gaps := delays[1:]
+
+	var problems []string
+	fast := 0
+	for _, gap := range gaps {
+		if gap < MinKeyInterval.Milliseconds() {
+			fast++
+		}
+	}
+	if fast > 2 && float64(fast) > maxFastKeys*float64(len(gaps)) {
+		problems = append(problems, fmt.Sprintf("%d of %d keys came less than %dms after the previous one",
+			fast, len(gaps), MinKeyInterval.Milliseconds()))
+	}
+
+	if len(gaps) >= burstKeys {
+		var window, fastest int64
+		for i, gap := range gaps {
+			window += gap
+			if i >= burstKeys {
+				window -= gaps[i-burstKeys]
+			}
+			if i >= burstKeys-1 && (i == burstKeys-1 || window < fastest) {
+				fastest = window
+			}
+		}
+		burst := time.Duration(fastest) * time.Millisecond
+		if burst <= 0 {
+			problems = append(problems, fmt.Sprintf("%d keys arrived at once", burstKeys))
+		} else if wpm := StandardWPM(burstKeys, burst); wpm > MaxBurstWPM {
+			problems = append(problems, fmt.Sprintf("a burst of %d keys at %.0f WPM", burstKeys, wpm))
+		}
+	}
+
+	// Pauses are breaks, not typing, so they are left out of the spread
+	var typing []float64
+	for _, gap := range gaps {
+		if gap <= maxKeyLatency.Milliseconds() {
+			typing = append(typing, float64(gap))
+		}
+	}
+	if len(typing) >= uniformKeys {
+		var sum, squares float64
+		for _, gap := range typing {
+			sum += gap
+		}
+		mean := sum / float64(len(typing))
+		for _, gap := range typing {
+			squares += (gap - mean) * (gap - mean)
+		}
+		if mean > 0 {
+			variation := math.Sqrt(squares/float64(len(typing))) / mean
+			if variation < minTimingVariation {
+				problems = append(problems, fmt.Sprintf("key timing is too even to be typed by hand (%.0f%% variation)", variation*100))
+			}
+		}
+	}
+	return problems
+}
+
+// checkReplay replays a keystroke log and compares the results with the claim
+func checkReplay(log *KeyLog, claim SessionClaim) error {
+	keys := log.Keystrokes()
+	last := keys[len(keys)-1].Time
+
+	// Logs with line lengths keep exact times; older ones lost up to a
+	// millisecond on every key
+	tolerance := 5 * time.Millisecond
+	if log.LineLengths == nil {
+		tolerance = time.Duration(len(keys)) * time.Millisecond
+	}
+	if claim.TimeLimit > 0 {
+		if claim.Duration > claim.TimeLimit || last > claim.Duration+tolerance {
+			return fmt.Errorf("keystrokes run %s into a %s test", formatSeconds(last), formatSeconds(claim.TimeLimit))
+		}
+	} else if diff := claim.Duration - last; diff > tolerance || diff < -tolerance {
+		return fmt.Errorf("session lasted %s but its keystrokes end at %s", formatSeconds(claim.Duration), formatSeconds(last))
+	}
+
+	stats, wpm, err := replayKeystrokes(keys, log.LineLengths, claim.TimeLimit, claim.Duration)
+	if err != nil {
+		return err
+	}
+
+	var differences []string
+	if stats.TotalCharacters != claim.Characters {
+		differences = append(differences, fmt.Sprintf("%d characters, not %d", stats.TotalCharacters, claim.Characters))
+	}
+	if stats.TotalMistakes != claim.Mistakes {
+		differences = append(differences, fmt.Sprintf("%d mistakes, not %d", stats.TotalMistakes, claim.Mistakes))
+	}
+	if math.Abs(stats.Accuracy-claim.Accuracy) > 0.05 {
+		differences = append(differences, fmt.Sprintf("%.1f%% accuracy, not %.1f%%", stats.Accuracy, claim.Accuracy))
+	}
+	if math.Abs(wpm-claim.WPM) > 0.05 {
+		differences = append(differences, fmt.Sprintf("%.1f WPM, not %.1f", wpm, claim.WPM))
+	}
+	if len(differences) > 0 {
+		return fmt.Errorf("keystrokes replay to %s", strings.Join(differences, ", "))
+	}
+	return nil
+}
+
+// replayKeystrokes runs a keystroke stream through the metrics engine the
+// way a session does. It returns the statistics and the speed in standard
+// words: every character and line break for practice, only correct ones
+// for timed tests. Lines whose length is not in lineLengths are worked out
+// from the keys, which is only possible when they did not end early;
+// otherwise ErrUnverifiable is returned.
+func replayKeystrokes(keys []Keystroke, lineLengths []int, timeLimit, duration time.Duration) (SessionStats, float64, error) {
+	metrics := NewMetrics()
+	metrics.SetWPMDefinition(WPMStandard, "")
+
+	var input, expected []rune
+	line, perfect := 0, 0
+	end := -1 // Where the line ended, if a key was typed past it
+	mistake := false
+	lineStart, firstKey := time.Duration(0), time.Duration(-1)
+
+	// original rebuilds the current line from the keys typed on it
+	original := func(length int) string {
+		text := make([]rune, length)
+		copy(text, expected)
+		return string(text)
+	}
+
+	for _, key := range keys {
+		if key.Char == KeySkip {
+			// A skipped line is not scored, but its length is still recorded
+			length := len(input)
+			if line < len(lineLengths) {
+				length = lineLengths[line]
+			}
+			if firstKey < 0 {
+				firstKey = key.Time
+			}
+			timing := LineTiming{Start: lineStart, FirstKey: firstKey, End: key.Time}
+			metrics.SkipLine(line, string(input), original(length), timing)
+
+			line++
+			input, expected = nil, nil
+			end, mistake = -1, false
+			lineStart, firstKey = key.Time, -1
+			continue
+		}
+
+		metrics.AddKeystroke()
+		if firstKey < 0 {
+			firstKey = key.Time
+		}
+
+		switch key.Char {
+		case KeyBackspace:
+			// Backspace never changes the input
+
+		case KeyEnter:
+			length := len(input)
+			switch {
+			case line < len(lineLengths):
+				length = lineLengths[line]
+			case key.Correct:
+			case key.Expected == KeyEnter && end >= 0:
+				length = end
+			default:
+				// The line ended early and its length was not recorded
+				return SessionStats{}, 0, ErrUnverifiable
+			}
+
+			timing := LineTiming{Start: lineStart, FirstKey: firstKey, End: key.Time}
+			metrics.AddLine(line, string(input), original(length), timing)
+			if key.Correct && !mistake {
+				perfect++
+			}
+
+			line++
+			input, expected = nil, nil
+			end, mistake = -1, false
+			lineStart, firstKey = key.Time, -1
+
+		default:
+			position := len(input)
+			input = append(input, key.Char)
+			switch {
+			case key.Correct:
+				expected = append(expected, key.Char)
+			case key.Expected != 0:
+				expected = append(expected, key.Expected)
+				mistake = true
+			default:
+				expected = append(expected, 0)
+				if end < 0 {
+					end = position
+				}
+				mistake = true
+			}
+		}
+	}
+
+	// A timed test scores the part of the line typed before time ran out
+	if timeLimit > 0 && len(input) > 0 {
+		length := len(input)
+		if line < len(lineLengths) {
+			length = lineLengths[line]
+		} else if end >= 0 {
+			length = end
+		}
+		timing := LineTiming{Start: lineStart, FirstKey: firstKey, End: duration}
+		metrics.AddPartialLine(line, string(input), original(length), timing)
+	}
+
+	stats := metrics.GetSessionStats(duration)
+	if timeLimit > 0 {
+		return stats, StandardWPM(stats.CorrectCharacters+perfect, duration), nil
+	}
+	return stats, StandardWPM(stats.TotalCharacters+stats.LinesCompleted, duration), nil
+}
+
+// formatSeconds formats a duration as seconds with one decimal
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
@@ -0,0 +1,145 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// typeSession types lines into a session on a fake clock, waiting gap(i)
+// before the i-th key, and returns its record. Text is pasted pastes times
+// before typing starts.
+func typeSession(lines []string, gap func(i int) time.Duration, pastes int) SessionRecord {
+	now := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := NewSession(lines)
+	session.SetClock(func() time.Time { return now })
+	session.Start()
+
+	for i := 0; i < pastes; i++ {
+		session.Paste()
+	}
+
+	key := 0
+	for _, line := range lines {
+		for _, char := range line {
+			now = now.Add(gap(key))
+			key++
+			session.TypeRune(char)
+		}
+		now = now.Add(gap(key))
+		key++
+		session.CompleteLine()
+	}
+	return NewSessionRecord("main.go", session)
+}
+
+// humanGap varies between 120ms and 300ms, the way a person types
+func humanGap(i int) time.Duration {
+	return time.Duration(120+(i*37)%180) * time.Millisecond
+}
+
+func TestSessionRecordValidate(t *testing.T) {
+	lines := []string{
+		"package main",
+		"func main() {",
+		"    fmt.Println(\"hello, world\")",
+		"}",
+	}
+
+	tests := []struct {
+		name   string
+		record func() SessionRecord
+		want   string // Part of the error, "" for a valid record
+	}{
+		{"valid log", func() SessionRecord {
+			return typeSession(lines, humanGap, 0)
+		}, ""},
+		{"tampered WPM", func() SessionRecord {
+			record := typeSession(lines, humanGap, 0)
+			record.WPM *= 2
+			return record
+		}, "WPM, not"},
+		{"tampered mistakes", func() SessionRecord {
+			record := typeSession(lines, humanGap, 0)
+			record.Mistakes = 3
+			return record
+		}, "0 mistakes, not 3"},
+		{"impossible key timing", func() SessionRecord {
+			return typeSession(lines, func(int) time.Duration { return time.Millisecond }, 0)
+		}, "came less than"},
+		{"too even to be typed", func() SessionRecord {
+			return typeSession(lines, func(int) time.Duration { return 150 * time.Millisecond }, 0)
+		}, "too even"},
+		{"pasted text", func() SessionRecord {
+			return typeSession(lines, humanGap, 1)
+		}, "text was pasted"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.record().Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Validate() = %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRecordValidateMissingLog(t *testing.T) {
+	record := typeSession([]string{"x := 1"}, humanGap, 0)
+	record.Keystrokes = nil
+	if err := record.Validate(); err != ErrUnverifiable {
+		t.Fatalf("Validate() = %v, want ErrUnverifiable", err)
+	}
+}
+
+func TestBundleResultValidateSnippetHash(t *testing.T) {
+	record := typeSession([]string{"package main", "func main() {}"}, humanGap, 0)
+	bundle := NewResultBundle("ann", []SessionRecord{record}, time.Now())
+
+	tests := []struct {
+		name  string
+		hash  string
+		valid bool
+	}{
+		{"from the session", record.ContentHash, true},
+		{"missing", "", true},
+		{"too short", "ab", false},
+		{"not hex", strings.Repeat("z", 64), false},
+		{"uppercase", strings.ToUpper(record.ContentHash), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := bundle.Results[0]
+			result.SnippetHash = tt.hash
+			err := result.Validate()
+			if tt.valid && err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			if !tt.valid && (err == nil || !strings.Contains(err.Error(), "snippet hash")) {
+				t.Fatalf("Validate() = %v, want a snippet hash error", err)
+			}
+		})
+	}
+}
+
+func TestSessionRecordValidatePasteOnly(t *testing.T) {
+	session := NewSession([]string{"x := 1"})
+	session.Start()
+	session.Paste()
+
+	record := NewSessionRecord("main.go", session)
+	if record.Keystrokes == nil || record.Keystrokes.Pastes != 1 {
+		t.Fatalf("Keystrokes = %+v, want a log with 1 paste", record.Keystrokes)
+	}
+	if err := record.Validate(); err == nil || !strings.Contains(err.Error(), "text was pasted") {
+		t.Fatalf("Validate() = %v, want an error containing %q", err, "text was pasted")
+	}
+}
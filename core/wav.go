@@ -0,0 +1,168 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// wavFormat describes the PCM data in a WAV file
+type wavFormat struct {
+	audioFormat   uint16
+	channels      uint16
+	sampleRate    uint32
+	bitsPerSample uint16
+}
+
+// LoadWAV reads a PCM WAV file and converts it to the audio format used
+// for playback (16-bit stereo at audioSampleRate)
+func LoadWAV(path string) ([]int16, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening sound: %v", err)
+	}
+	defer file.Close()
+
+	samples, format, err := decodeWAV(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sound %s: %v", path, err)
+	}
+	return convertSamples(samples, int(format.sampleRate), int(format.channels), audioSampleRate, audioChannels), nil
+}
+
+// decodeWAV reads 8- or 16-bit PCM samples from a RIFF WAV stream
+func decodeWAV(r io.Reader) ([]int16, wavFormat, error) {
+	var format wavFormat
+
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, format, fmt.Errorf("missing WAV header")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return nil, format, fmt.Errorf("not a WAV file")
+	}
+
+	haveFormat := false
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, format, fmt.Errorf("no audio data found")
+		}
+		id := string(chunk[0:4])
+		size := binary.LittleEndian.Uint32(chunk[4:8])
+
+		body := make([]byte, size+size%2) // Chunks are padded to an even size
+		if _, err := io.ReadFull(r, body); err != nil && !(id == "data" && err == io.ErrUnexpectedEOF) {
+			return nil, format, fmt.Errorf("truncated %q chunk", id)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, format, fmt.Errorf("invalid format chunk")
+			}
+			format = wavFormat{
+				audioFormat:   binary.LittleEndian.Uint16(body[0:2]),
+				channels:      binary.LittleEndian.Uint16(body[2:4]),
+				sampleRate:    binary.LittleEndian.Uint32(body[4:8]),
+				bitsPerSample: binary.LittleEndian.Uint16(body[14:16]),
+			}
+			// 1 is plain PCM, 0xFFFE is the extensible header used for the same data
+			if format.audioFormat != 1 && format.audioFormat != 0xFFFE {
+				return nil, format, fmt.Errorf("unsupported encoding %d (only PCM is supported)", format.audioFormat)
+			}
+			if format.bitsPerSample != 8 && format.bitsPerSample != 16 {
+				return nil, format, fmt.Errorf("unsupported sample size %d bits (use 8 or 16)", format.bitsPerSample)
+			}
+			if format.channels == 0 || format.sampleRate == 0 {
+				return nil, format, fmt.Errorf("invalid format chunk")
+			}
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return nil, format, fmt.Errorf("audio data before format chunk")
+			}
+			return pcmSamples(body, format.bitsPerSample), format, nil
+		}
+	}
+}
+
+// pcmSamples converts raw little-endian PCM bytes to 16-bit samples
+func pcmSamples(data []byte, bitsPerSample uint16) []int16 {
+	if bitsPerSample == 8 {
+		samples := make([]int16, len(data))
+		for i, b := range data {
+			samples[i] = int16(int(b)-128) << 8
+		}
+		return samples
+	}
+
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
+
+// convertSamples resamples interleaved audio to a new rate and channel count
+func convertSamples(samples []int16, fromRate, fromChannels, toRate, toChannels int) []int16 {
+	frames := len(samples) / fromChannels
+	outFrames := int(int64(frames) * int64(toRate) / int64(fromRate))
+	out := make([]int16, outFrames*toChannels)
+
+	for i := 0; i < outFrames; i++ {
+		// Linear interpolation between the two nearest source frames
+		pos := float64(i) * float64(fromRate) / float64(toRate)
+		frame := int(pos)
+		frac := pos - float64(frame)
+		next := frame + 1
+		if next >= frames {
+			next = frames - 1
+		}
+
+		for ch := 0; ch < toChannels; ch++ {
+			src := ch
+			if src >= fromChannels {
+				src = fromChannels - 1 // Mono is copied to every channel
+			}
+			a := float64(samples[frame*fromChannels+src])
+			b := float64(samples[next*fromChannels+src])
+			out[i*toChannels+ch] = int16(a + (b-a)*frac)
+		}
+	}
+	return out
+}
+
+// writeWAV writes 16-bit PCM samples as a WAV file
+func writeWAV(w io.Writer, samples []int16, sampleRate, channels int) error {
+	dataSize := uint32(len(samples) * 2)
+	blockAlign := uint16(channels * 2)
+
+	header := make([]byte, 44)
+	copy(header[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], 36+dataSize)
+	copy(header[8:12], "WAVE")
+	copy(header[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:20], 16)
+	binary.LittleEndian.PutUint16(header[20:22], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:24], uint16(channels))
+	binary.LittleEndian.PutUint32(header[24:28], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(header[28:32], uint32(sampleRate)*uint32(blockAlign))
+	binary.LittleEndian.PutUint16(header[32:34], blockAlign)
+	binary.LittleEndian.PutUint16(header[34:36], 16)
+	copy(header[36:40], "data")
+	binary.LittleEndian.PutUint32(header[40:44], dataSize)
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	data := make([]byte, dataSize)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(sample))
+	}
+	_, err := w.Write(data)
+	return err
+}
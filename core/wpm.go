@@ -0,0 +1,162 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WPMDefinition selects how typed code is counted in words for WPM
+type WPMDefinition string
+
+const (
+	WPMStandard WPMDefinition = "standard" // Five keystrokes per word, line breaks included
+	WPMTokens   WPMDefinition = "tokens"   // Whitespace-separated tokens
+	WPMLanguage WPMDefinition = "language" // Identifiers, literals and operators of the language
+)
+
+// WPMDefinitions lists every WPM definition
+var WPMDefinitions = []WPMDefinition{WPMStandard, WPMTokens, WPMLanguage}
+
+// DefaultWPMDefinition is the WPM definition used when none is configured.
+// Standard words compare fairly across languages.
+const DefaultWPMDefinition = WPMStandard
+
+// ParseWPMDefinition resolves a WPM definition name
+func ParseWPMDefinition(name string) (WPMDefinition, error) {
+	for _, d := range WPMDefinitions {
+		if string(d) == strings.ToLower(name) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown WPM definition: %s (available: %s)", name, strings.Join(WPMDefinitionNames(), ", "))
+}
+
+// WPMDefinitionNames returns the names of every WPM definition
+func WPMDefinitionNames() []string {
+	names := make([]string, len(WPMDefinitions))
+	for i, d := range WPMDefinitions {
+		names[i] = string(d)
+	}
+	return names
+}
+
+// Description explains what a word is under the definition
+func (d WPMDefinition) Description() string {
+	switch d {
+	case WPMTokens:
+		return "whitespace-separated tokens"
+	case WPMLanguage:
+		return "language tokens"
+	default:
+		return "5 characters per word"
+	}
+}
+
+// Words counts text as words under the definition. Line breaks in text
+// count as keystrokes for standard words and separate tokens otherwise.
+func (d WPMDefinition) Words(text, language string) float64 {
+	switch d {
+	case WPMTokens:
+		return float64(countWhitespaceTokens(text))
+	case WPMLanguage:
+		return float64(countCodeTokens(text, language))
+	default:
+		return float64(utf8.RuneCountInString(text)) / charsPerWord
+	}
+}
+
+// lineComments maps languages to their line comment marker; the rest use "//"
+var lineComments = map[string]string{
+	"Python": "#",
+}
+
+// countWhitespaceTokens counts runs of non-space characters
+func countWhitespaceTokens(text string) int {
+	return len(strings.Fields(text))
+}
+
+// countCodeTokens counts the tokens a programmer types: identifiers and
+// numbers, string literals, and operators or punctuation. Comments are
+// prose, so their words are counted instead.
+func countCodeTokens(text, language string) int {
+	comment := "//"
+	if marker, ok := lineComments[language]; ok {
+		comment = marker
+	}
+
+	tokens := 0
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(text[i:], comment):
+			// The comment runs to the end of the line
+			end := strings.IndexByte(text[i:], '\n')
+			if end == -1 {
+				end = len(text) - i
+			}
+			tokens += 1 + countWhitespaceTokens(text[i+len(comment):i+end])
+			i += end
+			continue
+		case isWordByte(c):
+			// Numbers keep their decimal point, e.g. 3.14
+			number := c >= '0' && c <= '9'
+			for i < len(text) && (isWordByte(text[i]) || (number && text[i] == '.')) {
+				i++
+			}
+		case c == '"' || c == '\'' || c == '`':
+			i = stringEnd(text, i)
+		default:
+			i += operatorLength(text[i:])
+		}
+		tokens++
+	}
+	return tokens
+}
+
+// isWordByte reports whether c can be part of an identifier or number.
+// Bytes of multi-byte characters are treated as letters.
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
+
+// stringEnd returns the index after the string literal starting at start.
+// An unterminated literal runs to the end of the line.
+func stringEnd(text string, start int) int {
+	quote := text[start]
+	for i := start + 1; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			if quote != '`' {
+				return i
+			}
+		}
+	}
+	return len(text)
+}
+
+// multiCharOperators are operators typed as one token, longest first
+var multiCharOperators = []string{
+	"<<=", ">>=", "**=", "//=", "...", "&^=", "===", "!==",
+	":=", "==", "!=", "<=", ">=", "&&", "||", "++", "--", "+=", "-=", "*=", "/=",
+	"%=", "&=", "|=", "^=", "->", "=>", "<<", ">>", "::", "**", "<-", "&^",
+}
+
+// operatorLength returns the length of the operator or punctuation at the
+// start of text
+func operatorLength(text string) int {
+	for _, op := range multiCharOperators {
+		if strings.HasPrefix(text, op) {
+			return len(op)
+		}
+	}
+	return 1
+}
@@ -1,9 +1,22 @@
 package main
 
 import (
+	"embed"
+	"io/fs"
+
 	"github.com/vamshi1188/SyntaxRush/cmd"
+	"github.com/vamshi1188/SyntaxRush/core"
 )
 
+// samples are the built-in practice files, used when no assets folder is
+// installed next to the binary
+//
+//go:embed assets/sample.*
+var samples embed.FS
+
 func main() {
+	if files, err := fs.Sub(samples, "assets"); err == nil {
+		core.SetSampleFiles(files)
+	}
 	cmd.Execute()
 }
@@ -0,0 +1,148 @@
+package theme
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colorblind-safe colors from the Okabe-Ito palette
+const (
+	okabeOrange     = "#E69F00"
+	okabeSkyBlue    = "#56B4E9"
+	okabeGreen      = "#009E73"
+	okabeYellow     = "#F0E442"
+	okabeBlue       = "#0072B2"
+	okabeVermillion = "#D55E00"
+	okabePurple     = "#CC79A7"
+)
+
+// NewDarkColorblindTheme creates a dark theme that tells correct and
+// incorrect characters apart by blue/orange and underline instead of
+// green/red
+func NewDarkColorblindTheme() *Theme {
+	t := NewDarkTheme()
+
+	t.CorrectChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeSkyBlue)).
+		Background(lipgloss.Color("#1e1e1e"))
+
+	t.IncorrectChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeOrange)).
+		Background(lipgloss.Color("#3a2a00")).
+		Bold(true).
+		Underline(true)
+
+	t.ExtraChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeVermillion)).
+		Background(lipgloss.Color("#3a2a00")).
+		Underline(true)
+
+	t.Error = t.Error.Foreground(lipgloss.Color(okabeVermillion))
+
+	t.Summary = t.Summary.
+		Foreground(lipgloss.Color(okabeSkyBlue)).
+		BorderForeground(lipgloss.Color(okabeSkyBlue))
+
+	t.PowerZen = lipgloss.NewStyle().Foreground(lipgloss.Color(okabePurple))
+	t.PowerFull = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeGreen))
+	t.PowerFlow = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeSkyBlue))
+	t.PowerFatigue = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeYellow))
+	t.PowerBurnout = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeVermillion))
+
+	t.KeyNext = t.KeyNext.Background(lipgloss.Color(okabeSkyBlue))
+	t.KeyMistake = t.KeyMistake.Background(lipgloss.Color(okabeVermillion))
+	t.KeyHeatLow = t.KeyHeatLow.Background(lipgloss.Color("#4a4000"))
+	t.KeyHeatMid = t.KeyHeatMid.Background(lipgloss.Color("#7a5200"))
+	t.KeyHeatHigh = t.KeyHeatHigh.Foreground(lipgloss.Color("#1e1e1e")).Background(lipgloss.Color(okabeOrange))
+
+	return t
+}
+
+// NewLightColorblindTheme creates the light counterpart of the colorblind theme
+func NewLightColorblindTheme() *Theme {
+	t := NewLightTheme()
+
+	t.CorrectChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeBlue)).
+		Background(lipgloss.Color("#E6F1FA"))
+
+	t.IncorrectChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeVermillion)).
+		Background(lipgloss.Color("#FCE9DC")).
+		Bold(true).
+		Underline(true)
+
+	t.ExtraChar = lipgloss.NewStyle().
+		Foreground(lipgloss.Color(okabeVermillion)).
+		Background(lipgloss.Color("#FCE9DC")).
+		Underline(true)
+
+	t.Error = t.Error.Foreground(lipgloss.Color(okabeVermillion))
+
+	t.Summary = t.Summary.
+		Foreground(lipgloss.Color(okabeBlue)).
+		BorderForeground(lipgloss.Color(okabeBlue))
+
+	t.PowerZen = lipgloss.NewStyle().Foreground(lipgloss.Color(okabePurple))
+	t.PowerFull = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeGreen))
+	t.PowerFlow = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeBlue))
+	t.PowerFatigue = lipgloss.NewStyle().Foreground(lipgloss.Color("#9A7B00"))
+	t.PowerBurnout = lipgloss.NewStyle().Foreground(lipgloss.Color(okabeVermillion))
+
+	t.KeyNext = t.KeyNext.Background(lipgloss.Color(okabeBlue))
+	t.KeyMistake = t.KeyMistake.Background(lipgloss.Color(okabeVermillion))
+	t.KeyHeatLow = t.KeyHeatLow.Background(lipgloss.Color("#FBF6C0"))
+	t.KeyHeatMid = t.KeyHeatMid.Background(lipgloss.Color("#F5D58A"))
+	t.KeyHeatHigh = t.KeyHeatHigh.Background(lipgloss.Color(okabeOrange))
+
+	return t
+}
+
+// NewMonochromeTheme creates a theme without any colors. Text attributes
+// (bold, underline, reverse video) carry all the meaning, so it works on
+// any terminal and with NO_COLOR.
+func NewMonochromeTheme() *Theme {
+	return &Theme{
+		// General styles
+		Title:    lipgloss.NewStyle().Bold(true).Padding(1, 2),
+		Text:     lipgloss.NewStyle(),
+		Error:    lipgloss.NewStyle().Bold(true),
+		Header:   lipgloss.NewStyle().Reverse(true).Bold(true).Padding(0, 1),
+		Controls: lipgloss.NewStyle().Italic(true),
+
+		// Code display styles
+		CodePane:    lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1),
+		CodeLine:    lipgloss.NewStyle(),
+		CurrentLine: lipgloss.NewStyle().Bold(true),
+		PaneTitle:   lipgloss.NewStyle().Bold(true).Padding(0, 1),
+
+		// Input styles
+		InputPane:     lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1),
+		CorrectChar:   lipgloss.NewStyle(),
+		IncorrectChar: lipgloss.NewStyle().Reverse(true),
+		RemainingChar: lipgloss.NewStyle().Faint(true),
+		ExtraChar:     lipgloss.NewStyle().Reverse(true).Underline(true),
+		Cursor:        lipgloss.NewStyle().Underline(true).Bold(true),
+		PaceMarker:    lipgloss.NewStyle().Italic(true).Underline(true),
+
+		// Metrics styles
+		MetricsPanel: lipgloss.NewStyle().Bold(true).Padding(0, 1),
+		Summary:      lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1, 2),
+
+		// Chart styles
+		ChartLine: lipgloss.NewStyle(),
+		ChartAxis: lipgloss.NewStyle().Faint(true),
+
+		// Muscle Power Indicator status styles
+		PowerZen:     lipgloss.NewStyle().Bold(true).Italic(true),
+		PowerFull:    lipgloss.NewStyle().Bold(true),
+		PowerFlow:    lipgloss.NewStyle(),
+		PowerFatigue: lipgloss.NewStyle().Italic(true),
+		PowerBurnout: lipgloss.NewStyle().Reverse(true),
+
+		// On-screen keyboard styles
+		Key:         lipgloss.NewStyle(),
+		KeyNext:     lipgloss.NewStyle().Reverse(true).Bold(true),
+		KeyMistake:  lipgloss.NewStyle().Reverse(true).Underline(true),
+		KeyHeatLow:  lipgloss.NewStyle().Underline(true),
+		KeyHeatMid:  lipgloss.NewStyle().Underline(true).Bold(true),
+		KeyHeatHigh: lipgloss.NewStyle().Underline(true).Bold(true).Italic(true),
+	}
+}
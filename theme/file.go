@@ -0,0 +1,202 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// StyleOverride describes changes to one style in a theme file. Unset
+// fields keep the value from the base theme.
+type StyleOverride struct {
+	Foreground       string `json:"foreground,omitempty"`
+	Background       string `json:"background,omitempty"`
+	BorderForeground string `json:"border_foreground,omitempty"`
+	Bold             *bool  `json:"bold,omitempty"`
+	Italic           *bool  `json:"italic,omitempty"`
+	Underline        *bool  `json:"underline,omitempty"`
+	Reverse          *bool  `json:"reverse,omitempty"`
+}
+
+// File is a user theme as stored on disk in JSON
+type File struct {
+	Name   string                   `json:"name"`
+	Base   string                   `json:"base"`
+	Styles map[string]StyleOverride `json:"styles"`
+}
+
+// styles maps theme file keys to the styles they override
+func (t *Theme) styles() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"title":          &t.Title,
+		"text":           &t.Text,
+		"error":          &t.Error,
+		"header":         &t.Header,
+		"controls":       &t.Controls,
+		"code_pane":      &t.CodePane,
+		"code_line":      &t.CodeLine,
+		"current_line":   &t.CurrentLine,
+		"pane_title":     &t.PaneTitle,
+		"input_pane":     &t.InputPane,
+		"correct_char":   &t.CorrectChar,
+		"incorrect_char": &t.IncorrectChar,
+		"remaining_char": &t.RemainingChar,
+		"extra_char":     &t.ExtraChar,
+		"cursor":         &t.Cursor,
+		"pace_marker":    &t.PaceMarker,
+		"metrics_panel":  &t.MetricsPanel,
+		"summary":        &t.Summary,
+		"chart_line":     &t.ChartLine,
+		"chart_axis":     &t.ChartAxis,
+		"power_zen":      &t.PowerZen,
+		"power_full":     &t.PowerFull,
+		"power_flow":     &t.PowerFlow,
+		"power_fatigue":  &t.PowerFatigue,
+		"power_burnout":  &t.PowerBurnout,
+		"key":            &t.Key,
+		"key_next":       &t.KeyNext,
+		"key_mistake":    &t.KeyMistake,
+		"key_heat_low":   &t.KeyHeatLow,
+		"key_heat_mid":   &t.KeyHeatMid,
+		"key_heat_high":  &t.KeyHeatHigh,
+	}
+}
+
+// StyleKeys returns the style names that theme files can override
+func StyleKeys() []string {
+	var keys []string
+	for key := range (&Theme{}).styles() {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// apply changes a style according to the override
+func (o StyleOverride) apply(style lipgloss.Style) lipgloss.Style {
+	if o.Foreground != "" {
+		style = style.Foreground(lipgloss.Color(o.Foreground))
+	}
+	if o.Background != "" {
+		style = style.Background(lipgloss.Color(o.Background))
+	}
+	if o.BorderForeground != "" {
+		style = style.BorderForeground(lipgloss.Color(o.BorderForeground))
+	}
+	if o.Bold != nil {
+		style = style.Bold(*o.Bold)
+	}
+	if o.Italic != nil {
+		style = style.Italic(*o.Italic)
+	}
+	if o.Underline != nil {
+		style = style.Underline(*o.Underline)
+	}
+	if o.Reverse != nil {
+		style = style.Reverse(*o.Reverse)
+	}
+	return style
+}
+
+// Build creates the theme described by the file on top of its base theme
+func (f *File) Build() (*Theme, error) {
+	base := f.Base
+	if base == "" {
+		base = DefaultName
+	}
+
+	t, err := Get(base)
+	if err != nil {
+		return nil, fmt.Errorf("theme %s: %v", f.Name, err)
+	}
+
+	if err := f.applyTo(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// applyTo applies the file's style overrides to t
+func (f *File) applyTo(t *Theme) error {
+	styles := t.styles()
+	for key, override := range f.Styles {
+		style, ok := styles[key]
+		if !ok {
+			return fmt.Errorf("theme %s: unknown style %q", f.Name, key)
+		}
+		*style = override.apply(*style)
+	}
+	return nil
+}
+
+// LoadFile reads a theme file and registers it under its name. Files
+// without a name are registered under their file name.
+func LoadFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading theme: %v", err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", fmt.Errorf("error parsing theme %s: %v", path, err)
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	// Build once now so mistakes are reported when the file is loaded
+	if _, err := file.Build(); err != nil {
+		return "", err
+	}
+
+	// Capture the base as it is now, so a theme that replaces its own base
+	// (e.g. a custom "dark" based on "dark") does not refer to itself
+	base := file.Base
+	if base == "" {
+		base = DefaultName
+	}
+	baseConstructor := registry[strings.ToLower(base)]
+
+	Register(file.Name, func() *Theme {
+		t := baseConstructor()
+		file.applyTo(t)
+		return t
+	})
+	return file.Name, nil
+}
+
+// LoadDir registers every *.json theme in dir. A missing directory is not
+// an error. Themes that fail to load are skipped and reported together, as
+// are TOML files, which are not a supported theme format.
+func LoadDir(dir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	var problems []string
+	tomlPaths, _ := filepath.Glob(filepath.Join(dir, "*.toml"))
+	for _, path := range tomlPaths {
+		problems = append(problems, fmt.Sprintf("%s: themes must be JSON files", filepath.Base(path)))
+	}
+	for _, path := range paths {
+		name, err := LoadFile(path)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		names = append(names, name)
+	}
+
+	if len(problems) > 0 {
+		return names, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return names, nil
+}
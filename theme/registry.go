@@ -0,0 +1,46 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultName is the theme used when none is configured
+const DefaultName = "dark"
+
+// MonochromeName is the theme used when colors are disabled
+const MonochromeName = "mono"
+
+// registry maps theme names to their constructors
+var registry = map[string]func() *Theme{
+	"dark":             NewDarkTheme,
+	"light":            NewLightTheme,
+	"dark-colorblind":  NewDarkColorblindTheme,
+	"light-colorblind": NewLightColorblindTheme,
+	MonochromeName:     NewMonochromeTheme,
+}
+
+// Register adds or replaces a named theme
+func Register(name string, constructor func() *Theme) {
+	registry[strings.ToLower(name)] = constructor
+}
+
+// Get returns a new instance of the named theme
+func Get(name string) (*Theme, error) {
+	constructor, ok := registry[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme: %s (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return constructor(), nil
+}
+
+// Names returns all registered theme names in alphabetical order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -24,10 +24,30 @@ type Theme struct {
 	RemainingChar lipgloss.Style
 	ExtraChar     lipgloss.Style
 	Cursor        lipgloss.Style
+	PaceMarker    lipgloss.Style // Where the target pace has reached
 
 	// Metrics styles
 	MetricsPanel lipgloss.Style
 	Summary      lipgloss.Style
+
+	// Chart styles
+	ChartLine lipgloss.Style
+	ChartAxis lipgloss.Style
+
+	// Muscle Power Indicator status styles
+	PowerZen     lipgloss.Style
+	PowerFull    lipgloss.Style
+	PowerFlow    lipgloss.Style
+	PowerFatigue lipgloss.Style
+	PowerBurnout lipgloss.Style
+
+	// On-screen keyboard styles
+	Key         lipgloss.Style
+	KeyNext     lipgloss.Style // The next key to press, and shift when needed
+	KeyMistake  lipgloss.Style // The key that was just mistyped
+	KeyHeatLow  lipgloss.Style // Keys mistyped under 10% of the time
+	KeyHeatMid  lipgloss.Style // 10% to 25%
+	KeyHeatHigh lipgloss.Style // 25% and more
 }
 
 // NewDarkTheme creates a dark theme with enhanced color scheme
@@ -102,6 +122,10 @@ func NewDarkTheme() *Theme {
 			Background(lipgloss.Color("#555555")).
 			Blink(true),
 
+		PaceMarker: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1e1e1e")).
+			Background(lipgloss.Color("#00BFFF")),
+
 		// Metrics styles
 		MetricsPanel: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00BFFF")).
@@ -114,6 +138,54 @@ func NewDarkTheme() *Theme {
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#00FF88")).
 			Padding(1, 2),
+		// Chart styles
+		ChartLine: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#00BFFF")),
+
+		ChartAxis: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#666666")),
+		// Muscle Power Indicator status styles
+		PowerZen: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13")),
+
+		PowerFull: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")),
+
+		PowerFlow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("12")),
+
+		PowerFatigue: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")),
+
+		PowerBurnout: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")),
+
+		// On-screen keyboard styles
+		Key: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#AAAAAA")).
+			Background(lipgloss.Color("#2a2a2a")),
+
+		KeyNext: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1e1e1e")).
+			Background(lipgloss.Color("#FFD700")).
+			Bold(true),
+
+		KeyMistake: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#FF5555")).
+			Bold(true),
+
+		KeyHeatLow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#DDDDDD")).
+			Background(lipgloss.Color("#4a3a1a")),
+
+		KeyHeatMid: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#8a4a1a")),
+
+		KeyHeatHigh: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#aa2a2a")),
 	}
 }
 
@@ -189,6 +261,10 @@ func NewLightTheme() *Theme {
 			Background(lipgloss.Color("#FCD34D")).
 			Blink(true),
 
+		PaceMarker: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#1D4ED8")),
+
 		// Metrics styles
 		MetricsPanel: lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#1D4ED8")).
@@ -201,5 +277,53 @@ func NewLightTheme() *Theme {
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#10B981")).
 			Padding(1, 2),
+		// Chart styles
+		ChartLine: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1D4ED8")),
+
+		ChartAxis: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#6B7280")),
+		// Muscle Power Indicator status styles
+		PowerZen: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#7C3AED")),
+
+		PowerFull: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#047857")),
+
+		PowerFlow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1D4ED8")),
+
+		PowerFatigue: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#B45309")),
+
+		PowerBurnout: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#DC2626")),
+
+		// On-screen keyboard styles
+		Key: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#374151")).
+			Background(lipgloss.Color("#E5E7EB")),
+
+		KeyNext: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#7C3AED")).
+			Bold(true),
+
+		KeyMistake: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#DC2626")).
+			Bold(true),
+
+		KeyHeatLow: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#374151")).
+			Background(lipgloss.Color("#FEF3C7")),
+
+		KeyHeatMid: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#374151")).
+			Background(lipgloss.Color("#FDBA74")),
+
+		KeyHeatHigh: lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#111827")).
+			Background(lipgloss.Color("#F87171")),
 	}
 }
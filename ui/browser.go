@@ -0,0 +1,602 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// File browser sizes
+const (
+	browserRows     = 14 // Entries listed at once
+	browserPreview  = 12 // Lines of the selected file previewed
+	recentFileCount = 5  // Recent files listed above the directory
+)
+
+// entryKind orders the groups of the file browser list
+type entryKind int
+
+const (
+	entryRecent entryKind = iota
+	entrySample
+	entryParent
+	entryDir
+	entryFile
+)
+
+// browserEntry is one row of the file browser
+type browserEntry struct {
+	kind  entryKind
+	name  string // Matched against the filter and shown
+	path  string // Absolute path; "" for samples only compiled into the binary
+	note  string // Extra text shown after the name
+	score int    // Fuzzy match score, higher is better
+}
+
+// isDir reports whether opening the entry changes directory
+func (e browserEntry) isDir() bool {
+	return e.kind == entryDir || e.kind == entryParent
+}
+
+// fileBrowser is the state of the file picker shown in StateFileSelect
+type fileBrowser struct {
+	dir       string   // Directory the query is relative to
+	query     []rune   // Typed filter, optionally with a directory prefix
+	cursor    int      // Selected entry
+	extension int      // Extension filter: 0 for supported, then each of supported, then all
+	supported []string // Extensions the parser accepts, sorted
+	recent    []string // Recently practiced files
+	samples   []browserEntry
+	entries   []browserEntry
+	err       string
+
+	listed  string         // Directory listing was read from
+	listing []browserEntry // Every entry of that directory
+	listErr error
+
+	previewPath string
+	preview     []string
+	previewErr  error
+}
+
+// newFileBrowser opens a browser on dir
+func newFileBrowser(dir string, supported, recent []string, samples []browserEntry) *fileBrowser {
+	sort.Strings(supported)
+	b := &fileBrowser{dir: dir, supported: supported, recent: recent, samples: samples}
+	b.refresh()
+	return b
+}
+
+// sampleEntries lists the built-in samples. Samples that are not installed
+// have no path until one is opened, so listing them writes no files.
+func sampleEntries() []browserEntry {
+	var entries []browserEntry
+	for _, sample := range core.Samples {
+		path, _ := core.InstalledAsset(sample.File)
+		entries = append(entries, browserEntry{kind: entrySample, name: sample.File, path: path, note: sample.Description})
+	}
+	return entries
+}
+
+// extensionFilter describes the extension filter: "" for every supported
+// extension, "*" for all files, or one extension
+func (b *fileBrowser) extensionFilter() string {
+	switch {
+	case b.extension == 0:
+		return ""
+	case b.extension > len(b.supported):
+		return "*"
+	default:
+		return b.supported[b.extension-1]
+	}
+}
+
+// cycleExtension moves to the next extension filter
+func (b *fileBrowser) cycleExtension() {
+	b.extension = (b.extension + 1) % (len(b.supported) + 2)
+	b.refresh()
+}
+
+// accepts reports whether a file passes the extension filter
+func (b *fileBrowser) accepts(name string) bool {
+	ext := filepath.Ext(name)
+	switch filter := b.extensionFilter(); filter {
+	case "*":
+		return true
+	case "":
+		for _, supported := range b.supported {
+			if ext == supported {
+				return true
+			}
+		}
+		return false
+	default:
+		return ext == filter
+	}
+}
+
+// splitQuery splits the query into the directory it points into and the
+// name filter after the last slash
+func (b *fileBrowser) splitQuery() (prefix, dir, name string) {
+	query := string(b.query)
+	slash := strings.LastIndex(query, "/")
+	if slash < 0 {
+		return "", b.dir, query
+	}
+	prefix = query[:slash+1]
+	return prefix, b.resolve(prefix), query[slash+1:]
+}
+
+// resolve turns typed text into an absolute path relative to the browser's
+// directory
+func (b *fileBrowser) resolve(text string) string {
+	if text == "~" || strings.HasPrefix(text, "~/") || filepath.IsAbs(text) {
+		return core.ExpandFilePath(text)
+	}
+	return filepath.Join(b.dir, text)
+}
+
+// readDir lists a directory, caching the result until it changes
+func (b *fileBrowser) readDir(dir string) {
+	if dir == b.listed {
+		return
+	}
+	b.listed = dir
+	b.listing = nil
+
+	entries, err := os.ReadDir(dir)
+	b.listErr = err
+	for _, entry := range entries {
+		kind := entryFile
+		if entry.IsDir() {
+			kind = entryDir
+		} else if info, err := os.Stat(filepath.Join(dir, entry.Name())); err == nil && info.IsDir() {
+			kind = entryDir // Symlink to a directory
+		}
+		b.listing = append(b.listing, browserEntry{kind: kind, name: entry.Name(), path: filepath.Join(dir, entry.Name())})
+	}
+}
+
+// refresh recomputes the entries matching the query
+func (b *fileBrowser) refresh() {
+	prefix, dir, name := b.splitQuery()
+	b.readDir(dir)
+
+	var candidates []browserEntry
+	if prefix == "" {
+		// Recent files and samples are offered until a directory is typed
+		for _, path := range b.recent {
+			candidates = append(candidates, browserEntry{kind: entryRecent, name: filepath.Base(path), path: path, note: shortenPath(filepath.Dir(path))})
+		}
+		candidates = append(candidates, b.samples...)
+	}
+	if parent := filepath.Dir(dir); parent != dir && name == "" {
+		candidates = append(candidates, browserEntry{kind: entryParent, name: "..", path: parent})
+	}
+	for _, entry := range b.listing {
+		// Hidden files only show when the filter asks for them
+		if strings.HasPrefix(entry.name, ".") && !strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.kind == entryFile && !b.accepts(entry.name) {
+			continue
+		}
+		candidates = append(candidates, entry)
+	}
+
+	b.entries = b.entries[:0]
+	for _, candidate := range candidates {
+		score, ok := fuzzyScore(candidate.name, name)
+		if !ok {
+			continue
+		}
+		candidate.score = score
+		b.entries = append(b.entries, candidate)
+	}
+
+	sort.SliceStable(b.entries, func(i, j int) bool {
+		a, c := b.entries[i], b.entries[j]
+		if a.score != c.score {
+			return a.score > c.score
+		}
+		if a.kind != c.kind {
+			return a.kind < c.kind
+		}
+		return strings.ToLower(a.name) < strings.ToLower(c.name)
+	})
+
+	if b.cursor >= len(b.entries) {
+		b.cursor = len(b.entries) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+}
+
+// selected returns the entry under the cursor
+func (b *fileBrowser) selected() (browserEntry, bool) {
+	if b.cursor < len(b.entries) {
+		return b.entries[b.cursor], true
+	}
+	return browserEntry{}, false
+}
+
+// move moves the cursor by delta, staying within the entries
+func (b *fileBrowser) move(delta int) {
+	b.cursor += delta
+	if b.cursor >= len(b.entries) {
+		b.cursor = len(b.entries) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+}
+
+// setQuery replaces the query and refilters
+func (b *fileBrowser) setQuery(query string) {
+	b.query = []rune(query)
+	b.cursor = 0
+	b.err = ""
+	b.refresh()
+}
+
+// enter makes dir the browser's directory and clears the query
+func (b *fileBrowser) enter(dir string) {
+	b.dir = dir
+	b.setQuery("")
+}
+
+// up moves to the parent directory
+func (b *fileBrowser) up() {
+	b.enter(filepath.Dir(b.dir))
+}
+
+// complete extends the name after the last slash to the longest prefix
+// shared by the matching entries of its directory. A single matching
+// directory is completed with a trailing slash to browse into it.
+func (b *fileBrowser) complete() {
+	prefix, _, name := b.splitQuery()
+
+	var matches []browserEntry
+	for _, entry := range b.listing {
+		if !strings.HasPrefix(strings.ToLower(entry.name), strings.ToLower(name)) {
+			continue
+		}
+		if strings.HasPrefix(entry.name, ".") && !strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.kind == entryFile && !b.accepts(entry.name) {
+			continue
+		}
+		matches = append(matches, entry)
+	}
+	if len(matches) == 0 {
+		b.err = "No matches for " + string(b.query)
+		return
+	}
+
+	if len(matches) == 1 {
+		completed := prefix + matches[0].name
+		if matches[0].kind == entryDir {
+			completed += "/"
+		}
+		b.setQuery(completed)
+		return
+	}
+
+	common := matches[0].name
+	for _, match := range matches[1:] {
+		common = commonPrefix(common, match.name)
+	}
+	if len([]rune(common)) > len([]rune(name)) {
+		b.setQuery(prefix + common)
+	}
+}
+
+// open returns the file to load for the selected entry, or browses into a
+// selected directory and returns "". Without a selection the query itself
+// is opened as a path or sample shortcut.
+func (b *fileBrowser) open() string {
+	entry, ok := b.selected()
+	if !ok {
+		query := strings.TrimSpace(string(b.query))
+		if query == "" {
+			return ""
+		}
+		if _, isSample := core.FindSample(query); isSample {
+			return core.ExpandFilePath(query)
+		}
+		path := b.resolve(query)
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			b.enter(path)
+			return ""
+		}
+		return path
+	}
+
+	if entry.isDir() {
+		b.enter(entry.path)
+		return ""
+	}
+	if entry.path == "" {
+		return core.FindAsset(entry.name)
+	}
+	return entry.path
+}
+
+// handleKey applies a key press to the browser and returns a file to load
+// once one is chosen
+func (b *fileBrowser) handleKey(msg tea.KeyMsg) string {
+	switch msg.Type {
+	case tea.KeyUp:
+		b.move(-1)
+	case tea.KeyDown:
+		b.move(1)
+	case tea.KeyPgUp:
+		b.move(-browserRows)
+	case tea.KeyPgDown:
+		b.move(browserRows)
+	case tea.KeyTab:
+		b.complete()
+	case tea.KeyCtrlE:
+		b.cycleExtension()
+	case tea.KeyEnter:
+		return b.open()
+	case tea.KeyLeft:
+		if len(b.query) == 0 {
+			b.up()
+		}
+	case tea.KeyRight:
+		if entry, ok := b.selected(); ok && entry.isDir() {
+			b.enter(entry.path)
+		}
+	case tea.KeyBackspace:
+		if len(b.query) == 0 {
+			b.up()
+		} else {
+			b.setQuery(string(b.query[:len(b.query)-1]))
+		}
+	case tea.KeySpace:
+		b.setQuery(string(b.query) + " ")
+	case tea.KeyRunes:
+		b.setQuery(string(b.query) + string(msg.Runes))
+	}
+	return ""
+}
+
+// loadPreview reads the first lines of the selected file, caching them
+func (b *fileBrowser) loadPreview(entry browserEntry) {
+	key := entry.path
+	if key == "" {
+		key = "sample:" + entry.name
+	}
+	if key == b.previewPath {
+		return
+	}
+	b.previewPath = key
+
+	if entry.path == "" {
+		b.preview, b.previewErr = core.ReadSampleHead(entry.name, browserPreview)
+		return
+	}
+
+	if entry.isDir() {
+		entries, err := os.ReadDir(entry.path)
+		b.preview, b.previewErr = nil, err
+		for _, child := range entries {
+			if len(b.preview) == browserPreview {
+				break
+			}
+			if strings.HasPrefix(child.Name(), ".") {
+				continue
+			}
+			name := child.Name()
+			if child.IsDir() {
+				name += "/"
+			}
+			b.preview = append(b.preview, name)
+		}
+		return
+	}
+	b.preview, b.previewErr = core.ReadHead(entry.path, browserPreview)
+}
+
+// fuzzyScore matches pattern against text as a case-insensitive
+// subsequence. Consecutive characters and matches at the start of a word
+// score higher; an empty pattern matches everything equally.
+func fuzzyScore(text, pattern string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	textRunes := []rune(strings.ToLower(text))
+	score := 0
+	pos := 0
+	previous := -2
+	for _, p := range strings.ToLower(pattern) {
+		found := false
+		for ; pos < len(textRunes); pos++ {
+			if textRunes[pos] != p {
+				continue
+			}
+			score++
+			if pos == previous+1 {
+				score += 5
+			}
+			if pos == 0 || !unicode.IsLetter(textRunes[pos-1]) && !unicode.IsDigit(textRunes[pos-1]) {
+				score += 8
+			}
+			previous = pos
+			pos++
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	// Prefer shorter names among equal matches
+	return score*100 - len(textRunes), true
+}
+
+// commonPrefix returns the longest shared prefix of two names, ignoring case
+func commonPrefix(a, b string) string {
+	ar, br := []rune(a), []rune(b)
+	n := 0
+	for n < len(ar) && n < len(br) && unicode.ToLower(ar[n]) == unicode.ToLower(br[n]) {
+		n++
+	}
+	return string(ar[:n])
+}
+
+// shortenPath replaces the home directory at the start of a path with ~
+func shortenPath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && home != "/" && strings.HasPrefix(path, home) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
+
+// openFileBrowser switches to the file picker in the current directory
+func (m *Model) openFileBrowser() {
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = "/"
+	}
+
+	recent := m.recentFiles(m.historyRecords(), recentFileCount)
+	m.browser = newFileBrowser(dir, m.parser.GetSupportedExtensions(), recent, sampleEntries())
+	m.state = StateFileSelect
+	m.message = ""
+}
+
+// renderFileSelect renders the file picker: the filter, the matching
+// entries and a preview of the selected one
+func (m *Model) renderFileSelect() string {
+	b := m.browser
+	title := m.theme.Title.Render("📁 SyntaxRush - Open File")
+
+	filter := "supported files (" + strings.Join(b.supported, " ") + ")"
+	switch ext := b.extensionFilter(); ext {
+	case "*":
+		filter = "all files"
+	case "":
+	default:
+		filter = ext + " files"
+	}
+	_, dir, _ := b.splitQuery()
+	location := m.theme.Text.Render(fmt.Sprintf("📂 %s │ showing %s", shortenPath(dir), filter))
+
+	input := m.theme.InputPane.Width(m.width-8).Padding(0, 1).Render("🔍 " + string(b.query) + "█")
+
+	listWidth := (m.width - 8) / 2
+	if listWidth > 60 {
+		listWidth = 60
+	}
+	list := m.renderBrowserList(listWidth)
+	preview := m.renderBrowserPreview(m.width - 8 - listWidth)
+	panes := lipgloss.JoinHorizontal(lipgloss.Top, list, "  ", preview)
+
+	sections := []string{title, location, input, panes}
+	if b.err != "" {
+		sections = append(sections, m.theme.Error.Render("❌ "+b.err))
+	}
+	if b.listErr != nil {
+		sections = append(sections, m.theme.Error.Render("❌ "+b.listErr.Error()))
+	}
+	sections = append(sections, "", m.theme.Controls.Render(
+		"Type to filter │ ↑/↓: Select │ Enter: Open │ Tab: Complete │ ←/Backspace: Up a folder │ Ctrl+E: File types │ Esc: Back"))
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderBrowserList renders the window of entries around the cursor
+func (m *Model) renderBrowserList(width int) string {
+	b := m.browser
+	start := 0
+	if b.cursor >= browserRows {
+		start = b.cursor - browserRows + 1
+	}
+	end := start + browserRows
+	if end > len(b.entries) {
+		end = len(b.entries)
+	}
+
+	icons := map[entryKind]string{entryRecent: "🕘", entrySample: "📦", entryParent: "⬆️ ", entryDir: "📁", entryFile: "📄"}
+
+	var rows []string
+	for i := start; i < end; i++ {
+		entry := b.entries[i]
+		name := entry.name
+		if entry.kind == entryDir {
+			name += "/"
+		}
+		row := truncateWidth(fmt.Sprintf("%s %s", icons[entry.kind], name), width-2)
+
+		// Notes fill whatever room the name leaves
+		note := ""
+		if room := width - 2 - lipgloss.Width(row) - 2; entry.note != "" && room > 3 {
+			note = "  " + truncateWidth(entry.note, room)
+		}
+
+		if i == b.cursor {
+			rows = append(rows, m.theme.CurrentLine.Render("▶ "+row+note))
+		} else {
+			rows = append(rows, m.theme.CodeLine.Render("  "+row)+m.theme.RemainingChar.Render(note))
+		}
+	}
+	if len(rows) == 0 {
+		rows = append(rows, m.theme.RemainingChar.Render("  No matching files"))
+	}
+	if len(b.entries) > browserRows {
+		rows = append(rows, m.theme.RemainingChar.Render(fmt.Sprintf("  %d of %d", b.cursor+1, len(b.entries))))
+	}
+
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(rows, "\n"))
+}
+
+// renderBrowserPreview renders the first lines of the selected file, or
+// the contents of the selected directory
+func (m *Model) renderBrowserPreview(width int) string {
+	b := m.browser
+	entry, ok := b.selected()
+	if !ok || width < 10 {
+		return ""
+	}
+	b.loadPreview(entry)
+
+	var lines []string
+	switch {
+	case b.previewErr != nil:
+		lines = []string{b.previewErr.Error()}
+	case len(b.preview) == 0:
+		lines = []string{"(empty)"}
+	default:
+		for _, line := range b.preview {
+			lines = append(lines, truncateWidth(strings.ReplaceAll(line, "\t", "    "), width-4))
+		}
+	}
+
+	title := m.theme.PaneTitle.Render("👀 " + truncateWidth(entry.name, width-6))
+	pane := m.theme.CodePane.Padding(0, 1).Width(width - 2).Render(m.theme.CodeLine.Render(strings.Join(lines, "\n")))
+	return lipgloss.JoinVertical(lipgloss.Left, title, pane)
+}
+
+// truncateWidth shortens text to a display width, ending with an ellipsis
+func truncateWidth(text string, width int) string {
+	if width <= 1 || lipgloss.Width(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && lipgloss.Width(string(runes)) > width-1 {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
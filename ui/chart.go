@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/vamshi1188/SyntaxRush/theme"
+)
+
+// sparkBlocks are the eight block heights used by sparklines
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a single row of block characters
+func Sparkline(values []float64) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	low, high := valueRange(values)
+
+	var sb strings.Builder
+	for _, v := range values {
+		level := 0
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[level])
+	}
+	return sb.String()
+}
+
+// brailleDots maps a dot position (column, row) inside a braille cell to its bit
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// BrailleChart renders values as a line chart of width x height braille
+// cells. Each cell holds a 2x4 grid of dots, so the chart resolution is
+// twice the width and four times the height.
+func BrailleChart(values []float64, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	cells := make([][]rune, height)
+	for row := range cells {
+		cells[row] = make([]rune, width)
+	}
+
+	if len(values) > 0 {
+		low, high := valueRange(values)
+		dotsX := width * 2
+		dotsY := height * 4
+
+		// Scale a value to a dot row, with row 0 at the top
+		toY := func(v float64) int {
+			if high == low {
+				return dotsY / 2
+			}
+			return dotsY - 1 - int(math.Round((v-low)/(high-low)*float64(dotsY-1)))
+		}
+
+		// Resample the values onto the available dot columns
+		points := make([]int, dotsX)
+		for x := range points {
+			idx := 0
+			if dotsX > 1 {
+				idx = int(math.Round(float64(x) * float64(len(values)-1) / float64(dotsX-1)))
+			}
+			points[x] = toY(values[idx])
+		}
+
+		for x, y := range points {
+			// Fill vertically towards the previous point to keep the line connected
+			from, to := y, y
+			if x > 0 {
+				prev := points[x-1]
+				if prev < from {
+					from = prev + 1
+				} else if prev > to {
+					to = prev - 1
+				}
+			}
+			for dy := from; dy <= to; dy++ {
+				cells[dy/4][x/2] |= brailleDots[x%2][dy%4]
+			}
+		}
+	}
+
+	lines := make([]string, height)
+	for row, cellRow := range cells {
+		var sb strings.Builder
+		for _, bits := range cellRow {
+			sb.WriteRune(0x2800 + bits)
+		}
+		lines[row] = sb.String()
+	}
+	return lines
+}
+
+// valueRange returns the smallest and largest of values
+func valueRange(values []float64) (float64, float64) {
+	low, high := values[0], values[0]
+	for _, v := range values[1:] {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	return low, high
+}
+
+// RenderLineChart renders a braille chart with min/max labels on the left
+func RenderLineChart(t *theme.Theme, values []float64, width, height int) string {
+	rows := BrailleChart(values, width, height)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	low, high := 0.0, 0.0
+	if len(values) > 0 {
+		low, high = valueRange(values)
+	}
+	highLabel := fmt.Sprintf("%7.1f ┤", high)
+	lowLabel := fmt.Sprintf("%7.1f ┤", low)
+	blankLabel := strings.Repeat(" ", 8) + "│"
+
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		label := blankLabel
+		if i == 0 {
+			label = highLabel
+		} else if i == len(rows)-1 {
+			label = lowLabel
+		}
+		lines[i] = t.ChartAxis.Render(label) + t.ChartLine.Render(row)
+	}
+	return strings.Join(lines, "\n")
+}
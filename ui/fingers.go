@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// fingerColumn is the width of one finger's column in the finger table
+const fingerColumn = 7
+
+// RenderFingerTable returns rows breaking keystrokes down by finger: keys
+// pressed, mistakes, accuracy, average time per key and shift presses,
+// followed by the same-finger bigram rate and the finger with most mistakes.
+// It returns nil when no keys could be mapped.
+func RenderFingerTable(analysis core.FingerAnalysis) []string {
+	if analysis.Keys() == 0 {
+		return nil
+	}
+
+	header := strings.Repeat(" ", 8)
+	keys, mistakes, accuracy, speed, shifts := "keys    ", "errors  ", "acc     ", "speed   ", "shift   "
+	for _, finger := range analysis.Fingers {
+		header += fmt.Sprintf("%*s", fingerColumn, finger.Finger.Short())
+		keys += fmt.Sprintf("%*d", fingerColumn, finger.Keys)
+		mistakes += fmt.Sprintf("%*d", fingerColumn, finger.Mistakes)
+		if finger.Keys == 0 {
+			accuracy += fmt.Sprintf("%*s", fingerColumn, "--")
+		} else {
+			accuracy += fmt.Sprintf("%*s", fingerColumn, fmt.Sprintf("%.1f%%", finger.Accuracy()))
+		}
+		if finger.Timed == 0 {
+			speed += fmt.Sprintf("%*s", fingerColumn, "--")
+		} else {
+			speed += fmt.Sprintf("%*s", fingerColumn, fmt.Sprintf("%dms", finger.AverageTime().Milliseconds()))
+		}
+		shifts += fmt.Sprintf("%*d", fingerColumn, finger.Shifts)
+	}
+
+	rows := []string{header, keys, mistakes, accuracy, speed, shifts}
+	rows = append(rows, fmt.Sprintf("🔁 Same-finger bigrams: %.1f%% (%d of %d)",
+		analysis.SameFingerRate(), analysis.SameFinger, analysis.Bigrams))
+
+	if worst, ok := mostMistakes(analysis); ok {
+		rows = append(rows, fmt.Sprintf("⚠️  Most mistakes: %s (%d of %d keys, %.1f%% accuracy)",
+			worst.Finger, worst.Mistakes, worst.Keys, worst.Accuracy()))
+	}
+	return rows
+}
+
+// mostMistakes returns the finger with the lowest accuracy among those that
+// made mistakes
+func mostMistakes(analysis core.FingerAnalysis) (core.FingerStats, bool) {
+	var worst core.FingerStats
+	found := false
+	for _, finger := range analysis.Fingers {
+		if finger.Mistakes == 0 {
+			continue
+		}
+		if !found || finger.Accuracy() < worst.Accuracy() {
+			worst = finger
+			found = true
+		}
+	}
+	return worst, found
+}
+
+// renderFingerReport returns the summary rows breaking the session down by
+// finger, or nil if there is nothing to show
+func (m *Model) renderFingerReport() []string {
+	if m.keyboard == nil {
+		return nil
+	}
+	analysis := core.AnalyzeFingers(m.keyboard, [][]core.Keystroke{m.session.Keystrokes()})
+	table := RenderFingerTable(analysis)
+	if table == nil {
+		return nil
+	}
+	return append([]string{"", fmt.Sprintf("🖐️  FINGERS (%s layout):", analysis.Layout)}, table...)
+}
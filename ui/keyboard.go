@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// keyboardPanelHeight is the number of rows the keyboard panel takes:
+// a blank line, the title, five rows of keys and the legend
+const keyboardPanelHeight = 8
+
+// keyFlash is how long a mistyped key stays marked on the keyboard
+const keyFlash = 700 * time.Millisecond
+
+// Heat tints start at these error rates, in percent
+const (
+	keyHeatMid  = 10
+	keyHeatHigh = 25
+)
+
+// Positions of the keys around the character rows
+var (
+	backspaceKey  = core.KeyPosition{Row: 0, Col: core.RowLength(0)}
+	tabKey        = core.KeyPosition{Row: 1, Col: -1}
+	capsKey       = core.KeyPosition{Row: 2, Col: -1}
+	enterKey      = core.KeyPosition{Row: 2, Col: core.RowLength(2)}
+	leftShiftKey  = core.KeyPosition{Row: 3, Col: -1}
+	rightShiftKey = core.KeyPosition{Row: 3, Col: core.RowLength(3)}
+	spaceKey      = core.KeyPosition{Row: core.SpaceRow}
+)
+
+// keyCell is one key drawn on the keyboard
+type keyCell struct {
+	position core.KeyPosition
+	label    string
+	width    int
+}
+
+// keyboardRows returns the keys of each row, sized so the rows line up
+// like a staggered keyboard. Character keys are labelled as they read with
+// shift held or not.
+func keyboardRows(layout *core.KeyboardLayout, shift bool) [][]keyCell {
+	characters := func(row int) []keyCell {
+		var cells []keyCell
+		for col := 0; col < core.RowLength(row); col++ {
+			position := core.KeyPosition{Row: row, Col: col}
+			label, _ := layout.Label(position, shift)
+			cells = append(cells, keyCell{position: position, label: string(label), width: 3})
+		}
+		return cells
+	}
+
+	rows := [][]keyCell{
+		append(characters(0), keyCell{backspaceKey, "bksp", 6}),
+		append([]keyCell{{tabKey, "tab", 6}}, characters(1)...),
+		append(append([]keyCell{{capsKey, "caps", 7}}, characters(2)...), keyCell{enterKey, "enter", 6}),
+		append(append([]keyCell{{leftShiftKey, "shift", 9}}, characters(3)...), keyCell{rightShiftKey, "shift", 8}),
+	}
+
+	// The space bar sits under the middle of the bottom row
+	indent := keyCell{position: core.KeyPosition{Row: -1}, width: 17}
+	rows = append(rows, []keyCell{indent, {spaceKey, "space", 23}})
+	return rows
+}
+
+// renderKeyboard renders the on-screen keyboard: the next key to press and
+// shift when it is needed, a flash on a key that was just mistyped, and a
+// tint on keys by how often they were mistyped this session
+func (m *Model) renderKeyboard() string {
+	if m.keyboard == nil {
+		return ""
+	}
+
+	keystrokes := m.session.Keystrokes()
+	counts := core.CountKeys(m.keyboard, keystrokes)
+	next, nextOK := m.nextKey()
+	flash, flashOK := m.flashKey(keystrokes)
+
+	var shiftKey core.KeyPosition
+	if nextOK && next.Shift {
+		shiftKey = rightShiftKey
+		if next.ShiftFinger() == core.LeftPinky {
+			shiftKey = leftShiftKey
+		}
+	}
+
+	style := func(position core.KeyPosition) lipgloss.Style {
+		switch {
+		case flashOK && position == flash:
+			return m.theme.KeyMistake
+		case nextOK && (position == next.Position() || (next.Shift && position == shiftKey)):
+			return m.theme.KeyNext
+		}
+
+		count := counts[position]
+		switch {
+		case count.Mistakes == 0:
+			return m.theme.Key
+		case count.ErrorRate() >= keyHeatHigh:
+			return m.theme.KeyHeatHigh
+		case count.ErrorRate() >= keyHeatMid:
+			return m.theme.KeyHeatMid
+		default:
+			return m.theme.KeyHeatLow
+		}
+	}
+
+	var lines []string
+	for _, row := range keyboardRows(m.keyboard, nextOK && next.Shift) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			text := centerLabel(cell.label, cell.width)
+			if cell.label == "" && cell.position.Row < 0 {
+				cells[i] = text // Spacing, not a key
+				continue
+			}
+			cells[i] = style(cell.position).Render(text)
+		}
+		lines = append(lines, strings.Join(cells, " "))
+	}
+
+	legend := m.theme.KeyNext.Render(" next ") + " " + m.theme.KeyMistake.Render(" mistyped ") +
+		"  mistakes: " + m.theme.KeyHeatLow.Render(" some ") + " " +
+		m.theme.KeyHeatMid.Render(" 10%+ ") + " " + m.theme.KeyHeatHigh.Render(" 25%+ ")
+	lines = append(lines, legend)
+
+	title := m.theme.PaneTitle.Render("⌨️  Keyboard (" + m.keyboard.Name + ")")
+	keyboard := lipgloss.PlaceHorizontal(m.width-2, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Left, lines...))
+	return lipgloss.JoinVertical(lipgloss.Left, title, keyboard)
+}
+
+// nextKey returns the key the current line expects next: the next
+// character, or Enter once the line has been typed
+func (m *Model) nextKey() (core.Key, bool) {
+	if m.session.IsFinished() {
+		return core.Key{}, false
+	}
+
+	return m.keyboard.Key(m.session.NextChar())
+}
+
+// flashKey returns the key of the last keystroke if it was a mistake made
+// within keyFlash
+func (m *Model) flashKey(keystrokes []core.Keystroke) (core.KeyPosition, bool) {
+	if len(keystrokes) == 0 {
+		return core.KeyPosition{}, false
+	}
+	last := keystrokes[len(keystrokes)-1]
+	if last.Correct || m.session.Elapsed()-last.Time > keyFlash {
+		return core.KeyPosition{}, false
+	}
+	if last.Char == core.KeyBackspace {
+		return backspaceKey, true
+	}
+
+	key, ok := m.keyboard.Key(last.Char)
+	return key.Position(), ok
+}
+
+// centerLabel pads a key label to width, centered
+func centerLabel(label string, width int) string {
+	padding := width - utf8.RuneCountInString(label)
+	if padding <= 0 {
+		return label
+	}
+	left := padding / 2
+	return strings.Repeat(" ", left) + label + strings.Repeat(" ", padding-left)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/vamshi1188/SyntaxRush/core"
 	"github.com/vamshi1188/SyntaxRush/theme"
 
@@ -15,33 +16,41 @@ import (
 // Model represents the application state
 type Model struct {
 	// File content and parsing
-	parser      *core.Parser
-	codeLines   []string
-	currentLine int
-	totalLines  int
-
-	// Typing state
-	userInput      string
-	correctChars   int
-	incorrectChars int
-	currentPos     int
-	lastMistakePos int // Track last mistake position to avoid repeated sounds
-
-	// Typing history for completed lines
-	completedLines map[int]string // Maps line number to user's typed input
-
-	// Metrics
-	metrics *core.Metrics
-	timer   *core.Timer
-	audio   *core.AudioManager
-	mpi     *core.MusclePowerIndicator // Muscle Power Indicator
+	parser  *core.Parser
+	session *core.Session // Typing engine: line advance, mistakes and metrics
+
+	// Feedback
+	audio *core.AudioManager
+	muted bool // Suppresses all sound, including the terminal bell
+
+	// Persistence
+	history  *core.History  // Where finished sessions are recorded, if set
+	files    *core.FileList // Recent and starred files, if set
+	filePath string         // Full path of the practiced file
 
 	// UI state
-	width         int
-	height        int
-	theme         *theme.Theme
-	viewportStart int
-	maxViewLines  int
+	width          int
+	height         int
+	theme          *theme.Theme
+	viewportStart  int
+	maxViewLines   int
+	showPowerGraph bool                 // Live power sparkline under the MPI panel
+	errorStyle     ErrorStyle           // How mistyped characters are marked
+	showReview     bool                 // Line-by-line mistake review on the summary
+	keyboard       *core.KeyboardLayout // Maps keys to fingers for the finger report
+	showKeyboard   bool                 // On-screen keyboard under the code pane
+
+	// Pacing
+	pacer       *core.Pacer // Target speed, nil when pacing is off
+	metronomeOn bool
+
+	// Timed tests
+	snippets    *core.SnippetStream // Endless supply of code, nil outside tests
+	deadlineOn  bool
+	testRank    int // Leaderboard position of the finished test, 0 if unknown
+	testEntries int // Tests on the leaderboard
+
+	runGen int // Identifies the current run; bumped on reset so stale timer messages are ignored
 
 	// App state
 	state    AppState
@@ -49,17 +58,38 @@ type Model struct {
 	filename string
 	quitting bool
 
-	// File input state
-	fileInput string
-	fileError string
+	// File picker state
+	browser *fileBrowser
+
+	// Key bound to each action while typing, and the command palette when
+	// it is open
+	keys    map[core.KeyAction]string
+	palette *commandPalette
+
+	// Playlist being typed, nil outside playlists
+	playlist *playlistRun
 
-	// Session summary
-	sessionComplete bool
-	finalStats      core.SessionStats
+	// Passes over the lines with mistakes since the file was loaded
+	redoPass int
+
+	// Starred and recent files on the welcome screen
+	quickFiles       []quickFile
+	quickFilesLoaded bool
 }
 
 type AppState int
 
+// ErrorStyle selects how mistyped characters are marked
+type ErrorStyle string
+
+const (
+	ErrorStyleColor     ErrorStyle = "color"     // Theme colors only
+	ErrorStyleUnderline ErrorStyle = "underline" // Underline the expected character
+	ErrorStyleInverse   ErrorStyle = "inverse"   // Inverse video on the expected character
+	ErrorStyleTyped     ErrorStyle = "typed"     // Show the typed character next to the expected one
+	ErrorStyleBelow     ErrorStyle = "below"     // Show typed characters in a row under the line
+)
+
 const (
 	StateWelcome AppState = iota
 	StateTyping
@@ -70,9 +100,13 @@ const (
 // playErrorSound plays a beep sound when a mistake is made
 // Uses the oto audio library for high-quality cross-platform sound
 func (m *Model) playErrorSound() {
+	if m.muted {
+		return
+	}
+
 	// Play audio if available
 	if m.audio != nil {
-		m.audio.PlayErrorBeep()
+		m.audio.Play(core.SoundError)
 	} else {
 		// Fallback to terminal bell if audio initialization failed
 		os.Stdout.Write([]byte("\a"))
@@ -80,15 +114,34 @@ func (m *Model) playErrorSound() {
 	}
 }
 
+// playSound plays the sound theme's sound for an event
+func (m *Model) playSound(event core.SoundEvent) {
+	if m.muted || m.audio == nil {
+		return
+	}
+	m.audio.Play(event)
+}
+
+// achievementStreaks are the correct-key streaks that earn an achievement
+var achievementStreaks = []int{25, 50, 100}
+
+// reachedAchievement reports whether the current streak just hit an achievement
+func (m *Model) reachedAchievement() bool {
+	streak, _ := m.session.MPI().GetStats()["correct_streak"].(int)
+	for _, target := range achievementStreaks {
+		if streak == target {
+			return true
+		}
+	}
+	return false
+}
+
 // TickMsg is sent every second to update metrics
 type TickMsg time.Time
 
 // NewModel creates a new application model
 func NewModel() *Model {
 	parser := core.NewParser()
-	metrics := core.NewMetrics()
-	timer := core.NewTimer()
-	mpi := core.NewMusclePowerIndicator()
 
 	// Initialize audio manager (gracefully handle errors)
 	audio, err := core.NewAudioManager()
@@ -124,25 +177,108 @@ func sum(nums []int) int {
 }`
 
 	model := &Model{
-		parser:         parser,
-		metrics:        metrics,
-		timer:          timer,
-		audio:          audio, // Add audio manager
-		mpi:            mpi,   // Add muscle power indicator
-		theme:          theme.NewDarkTheme(),
-		state:          StateWelcome,
-		maxViewLines:   20,
-		filename:       "sample.go",
-		lastMistakePos: -1,                   // Initialize mistake tracking
-		completedLines: make(map[int]string), // Initialize typing history
-	}
-
-	model.codeLines = strings.Split(sampleCode, "\n")
-	model.totalLines = len(model.codeLines)
+		parser:       parser,
+		session:      core.NewSession(strings.Split(sampleCode, "\n")),
+		audio:        audio, // Add audio manager
+		theme:        theme.NewDarkTheme(),
+		errorStyle:   ErrorStyleColor,
+		state:        StateWelcome,
+		maxViewLines: 20,
+		filename:     "sample.go",
+		filePath:     "sample.go",
+		keys:         core.DefaultConfig().KeyBindings(),
+	}
+	model.session.OnEvent(model.handleSessionEvent)
+	model.session.SetWPMDefinition(core.DefaultWPMDefinition, core.LanguageForFile(model.filePath))
+	model.keyboard, _ = core.GetKeyboardLayout(core.DefaultKeyboardLayout)
 
 	return model
 }
 
+// handleSessionEvent turns session events into audio feedback and screen changes
+func (m *Model) handleSessionEvent(event core.Event) {
+	switch event.Type {
+	case core.EventKeyTyped:
+		// Backspace is not allowed, and each new mistake gets one beep
+		if event.Backspace || event.NewMistake {
+			m.playErrorSound()
+		} else if event.Correct && m.reachedAchievement() {
+			m.playSound(core.SoundAchievement)
+		} else {
+			m.playSound(core.SoundKeypress)
+		}
+	case core.EventLineCompleted:
+		// Play success sound if line was typed correctly
+		if event.Perfect {
+			m.playSound(core.SoundLineComplete)
+		}
+
+		// Timed tests never run out of code
+		if m.snippets != nil && m.session.LineCount()-m.session.CurrentLineIndex() < testRefillLines {
+			m.session.AppendLines(m.snippets.Next()...)
+		}
+	case core.EventSessionFinished:
+		m.state = StateSummary
+		m.playSound(core.SoundSessionComplete)
+		m.recordSession()
+		m.finishPlaylistStep()
+	}
+}
+
+// recordSession appends the finished session to the history store.
+// Partial runs are not recorded, since they would rank under the whole
+// file's name.
+func (m *Model) recordSession() {
+	if m.history == nil {
+		return
+	}
+	if m.partialRun() {
+		m.message = "📝 Not saved to history: only runs of the whole file count toward bests and leaderboards"
+		return
+	}
+	record := core.NewSessionRecord(m.filePath, m.session)
+	if err := m.history.Append(record); err != nil {
+		m.message = "Could not save session history: " + err.Error()
+		return
+	}
+	m.invalidateQuickFiles()
+
+	if err := record.Validate(); err != nil && err != core.ErrUnverifiable {
+		m.message = "🚫 This session won't count on leaderboards: " + err.Error()
+	}
+
+	if record.Mode == core.ModeTest {
+		m.rankTest(record)
+	}
+}
+
+// partialRun reports whether the session covered only part of the file:
+// a pass over its mistakes, a run started partway through or a playlist
+// step of one function or line range
+func (m *Model) partialRun() bool {
+	if m.playlist != nil && m.playlist.steps[m.playlist.current].Partial() {
+		return true
+	}
+	return m.redoPass > 0 || m.session.StartLine() > 0
+}
+
+// rankTest finds a finished test's place on the personal leaderboard
+func (m *Model) rankTest(record core.SessionRecord) {
+	records, err := m.history.Load()
+	if err != nil {
+		return
+	}
+
+	board := core.TestLeaderboard(records, record.TimeLimit(), record.Language, record.Level())
+	for i, entry := range board {
+		if entry.Timestamp.Equal(record.Timestamp) && entry.WPM == record.WPM {
+			m.testRank = i + 1
+			break
+		}
+	}
+	m.testEntries = len(board)
+}
+
 // LoadFile loads a code file for typing practice
 func (m *Model) LoadFile(filepath string) error {
 	content, err := m.parser.ParseFile(filepath)
@@ -154,8 +290,31 @@ func (m *Model) LoadFile(filepath string) error {
 		return fmt.Errorf("file is empty")
 	}
 
-	m.codeLines = strings.Split(content, "\n")
-	m.totalLines = len(m.codeLines)
+	// Opening a file leaves any playlist
+	if err := m.loadContent(filepath, content); err != nil {
+		return err
+	}
+	m.leavePlaylist()
+	m.rememberFile(filepath)
+	return nil
+}
+
+// loadContent sets the code to type, read from the file at filepath
+func (m *Model) loadContent(filepath, content string) error {
+	// A timed test draws its snippets from the new file
+	if m.snippets != nil {
+		snippets, err := core.NewSnippetStream(content, time.Now().UnixNano())
+		if err != nil {
+			return err
+		}
+		m.snippets = snippets
+	}
+
+	// The language decides which lines easy difficulty skips
+	m.session.SetWPMDefinition(m.session.WPMDefinition(), core.LanguageForFile(filepath))
+	m.session.SetLines(strings.Split(content, "\n"))
+	m.filePath = filepath
+	m.redoPass = 0
 
 	// Extract just the filename for display
 	if lastSlash := strings.LastIndex(filepath, "/"); lastSlash != -1 {
@@ -169,45 +328,55 @@ func (m *Model) LoadFile(filepath string) error {
 	return nil
 }
 
-// expandFilePath expands shortcuts and handles relative paths
-func (m *Model) expandFilePath(input string) string {
-	// Handle built-in sample shortcuts
-	switch strings.ToLower(input) {
-	case "go", "sample.go":
-		return "assets/sample.go"
-	case "py", "python", "sample.py":
-		return "assets/sample.py"
-	case "js", "javascript", "sample.js":
-		return "assets/sample.js"
-	case "cpp", "c++", "sample.cpp":
-		return "assets/sample.cpp"
-	default:
-		// Return the input as-is for regular file paths
-		return input
+// resetSession resets the typing session
+func (m *Model) resetSession() {
+	if m.snippets != nil {
+		// Every timed test gets fresh snippets
+		m.session.SetLines(m.snippets.Lines(testBufferLines))
+	} else {
+		m.session.Reset()
 	}
+	m.resetRun()
 }
 
-// resetSession resets the typing session
-func (m *Model) resetSession() {
-	m.currentLine = 0
-	m.userInput = ""
-	m.correctChars = 0
-	m.incorrectChars = 0
-	m.currentPos = 0
-	m.lastMistakePos = -1 // Reset mistake tracking
+// restartAt starts the session over at a line of the same code
+func (m *Model) restartAt(line int) {
+	m.session.RestartFrom(line)
+	m.resetRun()
+	m.session.Start()
+	m.updateViewport()
+}
+
+// resetRun clears what the screen kept from the previous run
+func (m *Model) resetRun() {
 	m.viewportStart = 0
-	m.sessionComplete = false
-	m.completedLines = make(map[int]string) // Reset typing history
-	m.timer.Reset()
-	m.metrics.Reset()
-	m.mpi.Reset() // Reset muscle power indicator
+	m.message = ""
+	m.showReview = false
 	m.state = StateTyping
+
+	// Ticks from a previous run's timers are ignored
+	m.runGen++
+	m.metronomeOn = false
+	m.deadlineOn = false
+	m.testRank = 0
+	m.testEntries = 0
+}
+
+// beginSession resets and starts a session. Timed tests start their clock
+// on the first keystroke instead, like a stopwatch.
+func (m *Model) beginSession() {
+	m.resetSession()
+	if m.session.TimeLimit() == 0 {
+		m.session.Start()
+	}
 }
 
 // Init implements tea.Model
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		tickCmd(),
+		m.startMetronome(),
+		m.startDeadline(),
 	)
 }
 
@@ -218,22 +387,87 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// MetronomeMsg is sent on every beat of the pacing metronome
+type MetronomeMsg struct {
+	gen int
+}
+
+// metronomeCmd returns a command that sends the next metronome beat
+func metronomeCmd(gen int, interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return MetronomeMsg{gen: gen}
+	})
+}
+
+// startMetronome starts the metronome if pacing is on and a session is
+// running without one
+func (m *Model) startMetronome() tea.Cmd {
+	if m.pacer == nil || m.metronomeOn || m.state != StateTyping || !m.session.IsRunning() {
+		return nil
+	}
+	m.metronomeOn = true
+	return metronomeCmd(m.runGen, m.pacer.BeatInterval())
+}
+
+// DeadlineMsg is sent when a timed test should be over
+type DeadlineMsg struct {
+	gen int
+}
+
+// deadlineCmd returns a command that sends DeadlineMsg after d
+func deadlineCmd(gen int, d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return DeadlineMsg{gen: gen}
+	})
+}
+
+// startDeadline schedules the end of a timed test once its clock is running
+func (m *Model) startDeadline() tea.Cmd {
+	if m.session.TimeLimit() == 0 || m.deadlineOn || m.state != StateTyping || !m.session.IsRunning() {
+		return nil
+	}
+	m.deadlineOn = true
+	return deadlineCmd(m.runGen, m.session.Remaining())
+}
+
 // Update implements tea.Model
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.maxViewLines = m.height - 10 // Reserve space for UI elements
+		m.fitViewport()
 
 	case tea.KeyMsg:
-		return m.handleKeyPress(msg)
+		model, cmd := m.handleKeyPress(msg)
+		return model, tea.Batch(cmd, m.startMetronome(), m.startDeadline())
 
 	case TickMsg:
-		if m.state == StateTyping && m.timer.IsRunning() {
-			m.updateMetrics()
+		if m.state == StateTyping && m.session.IsRunning() {
+			m.session.UpdateMetrics()
 		}
 		return m, tickCmd()
+
+	case MetronomeMsg:
+		// The metronome stops with the session and restarts on the next key
+		if msg.gen != m.runGen || m.pacer == nil || m.state != StateTyping || !m.session.IsRunning() {
+			if msg.gen == m.runGen {
+				m.metronomeOn = false
+			}
+			return m, nil
+		}
+		m.playSound(core.SoundMetronome)
+		return m, metronomeCmd(m.runGen, m.pacer.BeatInterval())
+
+	case DeadlineMsg:
+		if msg.gen != m.runGen || m.state != StateTyping {
+			return m, nil
+		}
+		if !m.session.CheckTimeLimit() && m.session.IsRunning() {
+			// The timer has not quite reached the limit yet
+			return m, deadlineCmd(m.runGen, m.session.Remaining())
+		}
+		m.deadlineOn = false
 	}
 
 	return m, nil
@@ -241,6 +475,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress handles keyboard input
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.palette != nil {
+		return m.handlePaletteKeys(msg)
+	}
+
 	switch m.state {
 	case StateWelcome:
 		return m.handleWelcomeKeys(msg)
@@ -260,112 +498,155 @@ func (m *Model) handleWelcomeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+c", "q", "esc":
 		m.quitting = true
 		return m, tea.Quit
-	case "ctrl+u":
-		m.state = StateFileSelect
-		m.message = "Enter file path (or press Enter for sample): "
-		m.fileInput = ""
-		m.fileError = ""
+	case m.keys[core.ActionOpen]:
+		m.openFileBrowser()
+	case ":", m.keys[core.ActionPalette]:
+		m.openPalette()
+	case "f":
+		m.toggleStar()
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m.launchQuickFile(int(msg.Runes[0] - '0'))
 	case "enter", " ":
-		m.resetSession()
-		m.timer.Start()
+		m.beginSession()
 	}
 	return m, nil
 }
 
 // handleTypingKeys handles keys during typing practice
 func (m *Model) handleTypingKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "ctrl+c", "esc":
-		m.state = StateWelcome
-		m.timer.Stop()
+	// Pasted text arrives in one message from terminals with bracketed
+	// paste. It is not typed, and the session no longer counts.
+	if msg.Paste {
+		m.session.Paste()
+		m.message = "📋 Pasted text is not typed, and this session won't count on leaderboards"
 		return m, nil
-	case "ctrl+u":
-		m.state = StateFileSelect
-		m.message = "Enter file path: "
-		m.fileInput = ""
-		m.fileError = ""
+	}
+
+	if msg.String() == "ctrl+c" {
+		m.showMenu()
 		return m, nil
-	case "ctrl+r":
-		m.resetSession()
-		m.timer.Start()
+	}
+	if action, ok := m.boundAction(msg.String()); ok {
+		m.runAction(action)
 		return m, nil
+	}
+
+	switch msg.String() {
 	case "enter":
 		return m.handleLineComplete(), nil
 	case "backspace":
 		// Backspace is disabled during typing practice, but track the attempt
-		m.mpi.RecordKeystroke(0, false, true)
-		// Play error sound to indicate backspace is not allowed
-		m.playErrorSound()
+		m.session.Backspace()
 		return m, nil
-	default:
-		if len(msg.String()) == 1 {
-			oldInputLen := len(m.userInput)
-			m.userInput += msg.String()
-			m.currentPos = len(m.userInput)
-
-			// Check if this character is a mistake
-			currentLine := m.getCurrentLine()
-			isCorrect := false
-
-			if oldInputLen < len(currentLine) {
-				expectedChar := rune(currentLine[oldInputLen])
-				typedChar := rune(msg.String()[0])
-				isCorrect = expectedChar == typedChar
-
-				// If this is a new mistake (not repeating at same position)
-				if !isCorrect && m.lastMistakePos != oldInputLen {
-					m.playErrorSound()
-					m.lastMistakePos = oldInputLen
-				}
-			} else {
-				// Typing beyond the line length is also a mistake
-				isCorrect = false
-				if m.lastMistakePos != oldInputLen {
-					m.playErrorSound()
-					m.lastMistakePos = oldInputLen
-				}
-			}
-
-			// Record keystroke in MPI
-			m.mpi.RecordKeystroke(rune(msg.String()[0]), isCorrect, false)
+	case "tab":
+		// Unless the difficulty is hard, indentation is skipped for you,
+		// so tab is only typed where the line has one
+		if m.session.NextChar() == '\t' {
+			m.session.TypeRune('\t')
+		}
+		return m, nil
+	}
 
-			// Start timer on first keypress
-			if !m.timer.IsRunning() {
-				m.timer.Start()
+	// Several runes can arrive in one message, e.g. characters composed
+	// by an input method or keys typed faster than they are read
+	switch msg.Type {
+	case tea.KeySpace:
+		m.session.TypeRune(' ')
+	case tea.KeyRunes:
+		if !msg.Alt {
+			for _, char := range msg.Runes {
+				m.session.TypeRune(char)
 			}
 		}
 	}
 
-	m.updateMetrics()
 	return m, nil
 }
 
-// handleLineComplete processes when user presses Enter
-func (m *Model) handleLineComplete() *Model {
-	currentCode := m.getCurrentLine()
+// boundAction returns the action bound to a key, if any
+func (m *Model) boundAction(key string) (core.KeyAction, bool) {
+	for action, bound := range m.keys {
+		if bound == key {
+			return action, true
+		}
+	}
+	return "", false
+}
 
-	// Store the user's input for this completed line
-	m.completedLines[m.currentLine] = m.userInput
+// runAction runs an action bound to a key while typing
+func (m *Model) runAction(action core.KeyAction) {
+	switch action {
+	case core.ActionPalette:
+		m.openPalette()
+	case core.ActionRetry:
+		m.beginSession()
+	case core.ActionOpen:
+		m.openFileBrowser()
+	case core.ActionMenu:
+		m.showMenu()
+	case core.ActionSkipLine:
+		if inPractice(m) {
+			m.skipLine()
+		}
+	case core.ActionRestartLine:
+		if inPractice(m) {
+			m.restartAt(m.session.CurrentLineIndex())
+		}
+	case core.ActionPowerGraph:
+		m.showPowerGraph = !m.showPowerGraph
+	case core.ActionKeyboard:
+		m.SetShowKeyboard(!m.showKeyboard)
+	case core.ActionMute:
+		m.SetAudioEnabled(m.muted)
+	}
+}
 
-	// Calculate accuracy for this line
-	m.metrics.AddLine(m.userInput, currentCode)
+// showMenu leaves the session for the welcome screen
+func (m *Model) showMenu() {
+	m.state = StateWelcome
+	m.session.Stop()
+}
+
+// skipLine moves on from the current line without typing the rest of it
+func (m *Model) skipLine() {
+	m.session.SkipLine()
+	if !m.session.IsFinished() {
+		m.updateViewport()
+	}
+}
 
-	// Play success sound if line was typed correctly
-	if m.userInput == currentCode && m.audio != nil {
-		m.audio.PlaySuccessSound()
+// redoLines returns the lines of the finished session that were typed with
+// mistakes or skipped. Timed tests and playlists have none to redo.
+func (m *Model) redoLines() []string {
+	if !m.session.IsFinished() || m.session.TimeLimit() > 0 || m.playlist != nil {
+		return nil
+	}
+	code := m.session.Lines()
+	var lines []string
+	for _, line := range core.RedoLines(m.session.LineStats()) {
+		lines = append(lines, code[line])
 	}
+	return lines
+}
 
-	// Move to next line
-	m.currentLine++
-	m.userInput = ""
-	m.currentPos = 0
-	m.lastMistakePos = -1 // Reset mistake tracking for new line
+// redoMistakes starts a session of only the lines the finished session got
+// wrong or skipped
+func (m *Model) redoMistakes() {
+	lines := m.redoLines()
+	if len(lines) == 0 {
+		return
+	}
+	m.session.SetLines(lines)
+	m.redoPass++
+	m.beginSession()
+}
 
-	// Check if we've completed all lines
-	if m.currentLine >= m.totalLines {
-		m.completeSession()
-	} else {
-		// Update viewport if needed
+// handleLineComplete processes when user presses Enter
+func (m *Model) handleLineComplete() *Model {
+	m.session.CompleteLine()
+
+	// Update viewport if needed
+	if !m.session.IsFinished() {
 		m.updateViewport()
 	}
 
@@ -374,112 +655,80 @@ func (m *Model) handleLineComplete() *Model {
 
 // handleSummaryKeys handles keys in session summary
 func (m *Model) handleSummaryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.handlePlaylistSummaryKeys(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q", "esc":
 		m.quitting = true
 		return m, tea.Quit
 	case "r":
-		m.resetSession()
-		m.timer.Start()
+		m.beginSession()
 	case "u":
-		m.state = StateFileSelect
-		m.message = "Enter file path: "
-		m.fileInput = ""
-		m.fileError = ""
+		m.openFileBrowser()
+	case "d":
+		m.showReview = !m.showReview
+	case "m":
+		m.redoMistakes()
+	case ":", m.keys[core.ActionPalette]:
+		m.openPalette()
 	case "enter", " ":
 		m.state = StateWelcome
 	}
 	return m, nil
 }
 
-// handleFileSelectKeys handles file selection input
+// handleFileSelectKeys handles keys in the file picker
 func (m *Model) handleFileSelectKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "esc":
 		m.state = StateWelcome
 		m.message = ""
-		m.fileInput = ""
-		m.fileError = ""
-	case "enter":
-		if m.fileInput == "" {
-			// Empty input - use sample file
-			m.state = StateWelcome
-			m.message = ""
-			m.fileError = ""
-		} else {
-			// Try to load the specified file
-			filePath := m.expandFilePath(m.fileInput)
-			err := m.LoadFile(filePath)
-			if err != nil {
-				m.fileError = err.Error()
-			} else {
-				// Successfully loaded file
-				m.state = StateWelcome
-				m.message = "File loaded successfully: " + m.filename
-				m.fileInput = ""
-				m.fileError = ""
-			}
-		}
-	case "backspace":
-		if len(m.fileInput) > 0 {
-			m.fileInput = m.fileInput[:len(m.fileInput)-1]
-			m.fileError = "" // Clear error when user starts typing
-		}
-	default:
-		// Add printable characters to file input
-		if len(msg.String()) == 1 && msg.String()[0] >= 32 && msg.String()[0] <= 126 {
-			m.fileInput += msg.String()
-			m.fileError = "" // Clear error when user starts typing
-		}
+		return m, nil
 	}
-	return m, nil
-}
 
-// getCurrentLine returns the current line to type (trimmed of leading whitespace)
-func (m *Model) getCurrentLine() string {
-	if m.currentLine >= len(m.codeLines) {
-		return ""
+	path := m.browser.handleKey(msg)
+	if path == "" {
+		return m, nil
 	}
-	return strings.TrimLeft(m.codeLines[m.currentLine], " \t")
-}
-
-// getCurrentLineRaw returns the current line with original whitespace (for display)
-func (m *Model) getCurrentLineRaw() string {
-	if m.currentLine >= len(m.codeLines) {
-		return ""
+	if err := m.LoadFile(path); err != nil {
+		m.browser.err = err.Error()
+		return m, nil
 	}
-	return m.codeLines[m.currentLine]
+	m.state = StateWelcome
+	m.message = "File loaded successfully: " + m.filename
+	return m, nil
 }
 
 // updateViewport updates the viewport to keep current line visible
 func (m *Model) updateViewport() {
-	if m.currentLine >= m.viewportStart+m.maxViewLines {
-		m.viewportStart = m.currentLine - m.maxViewLines + 1
+	currentLine := m.session.CurrentLineIndex()
+	if currentLine >= m.viewportStart+m.maxViewLines {
+		m.viewportStart = currentLine - m.maxViewLines + 1
 	}
-	if m.currentLine < m.viewportStart {
-		m.viewportStart = m.currentLine
+	if currentLine < m.viewportStart {
+		m.viewportStart = currentLine
 	}
 	if m.viewportStart < 0 {
 		m.viewportStart = 0
 	}
 }
 
-// updateMetrics updates real-time typing metrics
-func (m *Model) updateMetrics() {
-	if m.timer.IsRunning() {
-		elapsed := m.timer.Elapsed()
-		m.metrics.UpdateRealTime(m.userInput, m.getCurrentLine(), elapsed)
+// fitViewport sizes the code pane to the window, making room for the
+// keyboard panel when it is shown
+func (m *Model) fitViewport() {
+	if m.height == 0 {
+		return
 	}
-}
-
-// completeSession finishes the typing session
-func (m *Model) completeSession() {
-	m.timer.Stop()
-	m.sessionComplete = true
-	m.state = StateSummary
-
-	// Calculate final statistics
-	m.finalStats = m.metrics.GetSessionStats(m.timer.Elapsed())
+	m.maxViewLines = m.height - 10 // Reserve space for UI elements
+	if m.showKeyboard {
+		m.maxViewLines -= keyboardPanelHeight
+	}
+	if m.maxViewLines < 3 {
+		m.maxViewLines = 3
+	}
+	m.updateViewport()
 }
 
 // View implements tea.Model
@@ -488,18 +737,22 @@ func (m *Model) View() string {
 		return "Thanks for using CodeType!\n"
 	}
 
+	var view string
 	switch m.state {
 	case StateWelcome:
-		return m.renderWelcome()
+		view = m.renderWelcome()
 	case StateTyping:
-		return m.renderTyping()
+		view = m.renderTyping()
 	case StateSummary:
-		return m.renderSummary()
+		view = m.renderSummary()
 	case StateFileSelect:
-		return m.renderFileSelect()
+		view = m.renderFileSelect()
 	}
 
-	return ""
+	if m.palette != nil {
+		view = lipgloss.JoinVertical(lipgloss.Left, view, m.renderPalette())
+	}
+	return view
 }
 
 // Cleanup properly closes audio resources
@@ -509,32 +762,149 @@ func (m *Model) Cleanup() {
 	}
 }
 
-// SetAudioEnabled enables or disables audio feedback
+// SetAudioEnabled enables or disables audio feedback. Audio is paused
+// rather than closed, so it can be turned back on while running.
 func (m *Model) SetAudioEnabled(enabled bool) {
-	if !enabled && m.audio != nil {
+	m.muted = !enabled
+	if m.audio != nil {
+		m.audio.SetEnabled(enabled)
+	}
+}
+
+// SetAudio replaces the audio manager used for sound effects
+func (m *Model) SetAudio(audio *core.AudioManager) {
+	if m.audio != nil && m.audio != audio {
 		m.audio.Close()
-		m.audio = nil
 	}
+	m.audio = audio
+}
+
+// Audio returns the audio manager, or nil if audio is unavailable
+func (m *Model) Audio() *core.AudioManager {
+	return m.audio
+}
+
+// SetTheme changes the styles used for rendering
+func (m *Model) SetTheme(t *theme.Theme) {
+	m.theme = t
+}
+
+// SetErrorStyle changes how mistyped characters are marked
+func (m *Model) SetErrorStyle(style ErrorStyle) {
+	m.errorStyle = style
+}
+
+// SetPace sets the target speed for the metronome and pace marker; zero
+// turns pacing off
+func (m *Model) SetPace(wpm float64) {
+	if wpm <= 0 {
+		m.pacer = nil
+		return
+	}
+	m.pacer = core.NewPacer(wpm)
+}
+
+// Timed tests keep this many lines ahead of the typist
+const (
+	testBufferLines = 30
+	testRefillLines = 10
+)
+
+// SetTimedTest turns the model into a timed test of the given length,
+// dealing random snippets of the loaded code without end
+func (m *Model) SetTimedTest(limit time.Duration, seed int64) error {
+	snippets, err := core.NewSnippetStream(strings.Join(m.session.Lines(), "\n"), seed)
+	if err != nil {
+		return err
+	}
+
+	m.snippets = snippets
+	m.session.SetTimeLimit(limit)
+	m.resetSession()
+	m.state = StateWelcome
+	return nil
+}
+
+// SetWPMDefinition selects how words are counted for WPM
+func (m *Model) SetWPMDefinition(definition core.WPMDefinition) {
+	m.session.SetWPMDefinition(definition, core.LanguageForFile(m.filePath))
+}
+
+// SetKeyBindings sets the key bound to each action while typing. Actions
+// left out have no key.
+func (m *Model) SetKeyBindings(keys map[core.KeyAction]string) {
+	m.keys = keys
+}
+
+// SetDifficulty selects how much of each line is typed. Personal bests
+// are kept per difficulty, so the file list is reloaded.
+func (m *Model) SetDifficulty(difficulty core.Difficulty) {
+	m.session.SetDifficulty(difficulty)
+	m.invalidateQuickFiles()
+}
+
+// SetKeyboardLayout selects the layout keystrokes are mapped to fingers with
+func (m *Model) SetKeyboardLayout(layout *core.KeyboardLayout) {
+	m.keyboard = layout
+}
+
+// SetShowKeyboard shows or hides the on-screen keyboard
+func (m *Model) SetShowKeyboard(show bool) {
+	m.showKeyboard = show
+	m.fitViewport()
+}
+
+// KeyboardLayout returns the layout keystrokes are mapped to fingers with
+func (m *Model) KeyboardLayout() *core.KeyboardLayout {
+	return m.keyboard
+}
+
+// SetHistory sets the store that finished sessions are recorded to
+func (m *Model) SetHistory(history *core.History) {
+	m.history = history
+	m.invalidateQuickFiles()
+}
+
+// SetFileList sets where recently opened and starred files are kept
+func (m *Model) SetFileList(files *core.FileList) {
+	m.files = files
+	m.invalidateQuickFiles()
+}
+
+// SetClock replaces the time source used for timing and power tracking
+func (m *Model) SetClock(clock core.Clock) {
+	m.session.SetClock(clock)
+}
+
+// IsSessionComplete reports whether every line has been typed
+func (m *Model) IsSessionComplete() bool {
+	return m.session.IsFinished()
+}
+
+// Session returns the typing engine driving this model
+func (m *Model) Session() *core.Session {
+	return m.session
 }
 
 // StartPracticeDirectly skips welcome screen and starts practice immediately
 func (m *Model) StartPracticeDirectly() {
-	m.resetSession()
-	m.timer.Start()
+	m.beginSession()
+}
+
+// TestRank returns a finished timed test's leaderboard position and the
+// number of tests on that leaderboard; the position is 0 if unknown
+func (m *Model) TestRank() (int, int) {
+	return m.testRank, m.testEntries
 }
 
 // GetFinalStats returns the final session statistics
 func (m *Model) GetFinalStats() core.SessionStats {
-	if m.sessionComplete {
-		return m.finalStats
-	}
-	// Return current stats if session is ongoing
-	return m.metrics.GetSessionStats(m.timer.Elapsed())
+	return m.session.Stats()
 }
 
 // GetMPIStats returns muscle power indicator statistics
 func (m *Model) GetMPIStats() map[string]interface{} {
-	return m.mpi.GetStats()
+	return m.session.MPI().GetStats()
 }
 
 // Helper methods for rendering will be implemented in view.go
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteRows is how many suggestions the command palette shows at once
+const paletteRows = 8
+
+// commandPalette is the open command palette. The session keeps running
+// while it is open.
+type commandPalette struct {
+	input    string
+	selected int
+	err      string
+}
+
+// paletteCommand is a command that can be run from the palette
+type paletteCommand struct {
+	name        string
+	args        string // Placeholder for the argument, "" if it takes none
+	description string
+	choices     func() []string // Arguments offered as suggestions, if any
+
+	// Where the command can be used; nil means everywhere
+	available func(m *Model) bool
+	run       func(m *Model, arg string) error
+}
+
+// paletteSuggestion is a row offered in the palette
+type paletteSuggestion struct {
+	text        string // Input the row completes to
+	label       string
+	description string
+}
+
+// inPractice reports whether the session can move between lines, which
+// timed tests do not allow
+func inPractice(m *Model) bool {
+	return m.session.TimeLimit() == 0
+}
+
+// whileTyping reports whether a practice session is being typed
+func whileTyping(m *Model) bool {
+	return m.state == StateTyping && inPractice(m)
+}
+
+// canRedo reports whether the finished session has lines to type again
+func canRedo(m *Model) bool {
+	return len(m.redoLines()) > 0
+}
+
+// paletteCommands are the commands offered in the palette, in the order
+// they are listed
+var paletteCommands = []paletteCommand{
+	{name: "line", args: "<number>", description: "Start over at a line", available: whileTyping, run: (*Model).jumpToLine},
+	{name: "snippet", args: "<name>", description: "Start over at a function or type", available: whileTyping, run: (*Model).jumpToSymbol},
+	{name: "skip", description: "Skip the rest of the current line", available: whileTyping, run: func(m *Model, _ string) error {
+		m.skipLine()
+		return nil
+	}},
+	{name: "restart-line", description: "Start over at the current line", available: whileTyping, run: func(m *Model, _ string) error {
+		m.restartAt(m.session.CurrentLineIndex())
+		return nil
+	}},
+	{name: "redo", description: "Type again only the lines with mistakes", available: canRedo, run: func(m *Model, _ string) error {
+		m.redoMistakes()
+		return nil
+	}},
+	{name: "retry", description: "Start the session over", run: func(m *Model, _ string) error {
+		m.beginSession()
+		return nil
+	}},
+	{name: "open", description: "Open a file", run: func(m *Model, _ string) error {
+		m.openFileBrowser()
+		return nil
+	}},
+	{name: "keyboard", description: "Show or hide the on-screen keyboard", run: func(m *Model, _ string) error {
+		m.SetShowKeyboard(!m.showKeyboard)
+		return nil
+	}},
+	{name: "graph", description: "Show or hide the power graph", run: func(m *Model, _ string) error {
+		m.showPowerGraph = !m.showPowerGraph
+		return nil
+	}},
+	{name: "mute", description: "Turn sound off or on", run: func(m *Model, _ string) error {
+		m.SetAudioEnabled(m.muted)
+		return nil
+	}},
+	{name: "theme", args: "<name>", description: "Switch theme for this run", choices: theme.Names, run: (*Model).switchTheme},
+	{name: "difficulty", args: "<level>", description: "Change how much of each line is typed", choices: core.DifficultyNames, run: (*Model).changeDifficulty},
+	{name: "menu", description: "Back to the welcome screen", run: func(m *Model, _ string) error {
+		m.showMenu()
+		return nil
+	}},
+	{name: "quit", description: "Quit SyntaxRush", run: func(m *Model, _ string) error {
+		m.quitting = true
+		return nil
+	}},
+}
+
+// openPalette opens the command palette over the current screen
+func (m *Model) openPalette() {
+	m.palette = &commandPalette{}
+}
+
+// findCommand returns the palette command with a name, if it can be used
+func (m *Model) findCommand(name string) (paletteCommand, bool) {
+	for _, command := range paletteCommands {
+		if command.name == name && (command.available == nil || command.available(m)) {
+			return command, true
+		}
+	}
+	return paletteCommand{}, false
+}
+
+// parsePaletteInput splits palette input into a command name and its
+// argument. ":<n>" is short for "line <n>", as in vim.
+func parsePaletteInput(input string) (string, string) {
+	input = strings.TrimLeft(input, " ")
+	if rest, ok := strings.CutPrefix(input, ":"); ok {
+		return "line", strings.TrimSpace(rest)
+	}
+	name, arg, _ := strings.Cut(input, " ")
+	return strings.ToLower(name), strings.TrimSpace(arg)
+}
+
+// paletteSuggestions returns the rows matching the palette input: commands
+// starting with what was typed, or the arguments of the command typed
+func (m *Model) paletteSuggestions() []paletteSuggestion {
+	input := m.palette.input
+	name, arg := parsePaletteInput(input)
+
+	var suggestions []paletteSuggestion
+	if strings.Contains(input, " ") || strings.HasPrefix(strings.TrimSpace(input), ":") {
+		command, ok := m.findCommand(name)
+		if !ok {
+			return nil
+		}
+		if command.choices == nil {
+			return []paletteSuggestion{{text: input, label: command.name + " " + command.args, description: command.description}}
+		}
+		for _, choice := range command.choices() {
+			if strings.HasPrefix(choice, strings.ToLower(arg)) {
+				text := command.name + " " + choice
+				suggestions = append(suggestions, paletteSuggestion{text: text, label: text, description: command.description})
+			}
+		}
+		return suggestions
+	}
+
+	for _, command := range paletteCommands {
+		if !strings.HasPrefix(command.name, name) || (command.available != nil && !command.available(m)) {
+			continue
+		}
+		suggestion := paletteSuggestion{text: command.name, label: command.name, description: command.description}
+		if command.args != "" {
+			suggestion.text += " "
+			suggestion.label += " " + command.args
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions
+}
+
+// handlePaletteKeys edits, navigates and runs the command palette
+func (m *Model) handlePaletteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	suggestions := m.paletteSuggestions()
+
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.palette = nil
+		return m, nil
+	case "up", "ctrl+p":
+		if p.selected > 0 {
+			p.selected--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if p.selected < len(suggestions)-1 {
+			p.selected++
+		}
+		return m, nil
+	case "tab":
+		if p.selected < len(suggestions) {
+			p.input = suggestions[p.selected].text
+			p.selected, p.err = 0, ""
+		}
+		return m, nil
+	case "enter":
+		return m.runPaletteInput(suggestions)
+	case "backspace":
+		if input := []rune(p.input); len(input) > 0 {
+			p.input = string(input[:len(input)-1])
+		}
+	case "ctrl+u":
+		p.input = ""
+	default:
+		switch msg.Type {
+		case tea.KeySpace:
+			p.input += " "
+		case tea.KeyRunes:
+			if msg.Alt || msg.Paste {
+				return m, nil
+			}
+			p.input += string(msg.Runes)
+		default:
+			return m, nil
+		}
+	}
+	p.selected, p.err = 0, ""
+	return m, nil
+}
+
+// runPaletteInput runs the selected suggestion, or what was typed when
+// nothing matches. A command still missing its argument is completed
+// instead, so the argument can be typed.
+func (m *Model) runPaletteInput(suggestions []paletteSuggestion) (tea.Model, tea.Cmd) {
+	p := m.palette
+	input := p.input
+	if p.selected < len(suggestions) {
+		input = suggestions[p.selected].text
+	}
+
+	name, arg := parsePaletteInput(input)
+	command, ok := m.findCommand(name)
+	if !ok {
+		p.err = fmt.Sprintf("Unknown command: %s", name)
+		if name == "" {
+			p.err = "Type a command"
+		}
+		return m, nil
+	}
+	if command.args != "" && arg == "" {
+		p.input = command.name + " "
+		p.selected, p.err = 0, ""
+		return m, nil
+	}
+
+	if err := command.run(m, arg); err != nil {
+		p.err = err.Error()
+		return m, nil
+	}
+	m.palette = nil
+	if m.quitting {
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// jumpToLine starts the session over at a line number counted from 1
+func (m *Model) jumpToLine(arg string) error {
+	line, err := strconv.Atoi(arg)
+	if err != nil || line < 1 || line > m.session.LineCount() {
+		return fmt.Errorf("no line %s (use 1 to %d)", arg, m.session.LineCount())
+	}
+	m.restartAt(line - 1)
+	return nil
+}
+
+// jumpToSymbol starts the session over at the declaration of a function,
+// method, type or class
+func (m *Model) jumpToSymbol(name string) error {
+	line, err := core.FindSymbol(m.session.Lines(), name)
+	if err != nil {
+		return err
+	}
+	m.restartAt(line)
+	return nil
+}
+
+// switchTheme changes the theme until SyntaxRush exits
+func (m *Model) switchTheme(name string) error {
+	t, err := theme.Get(name)
+	if err != nil {
+		return err
+	}
+	m.SetTheme(t)
+	return nil
+}
+
+// changeDifficulty switches difficulty and starts over at the current
+// line; a timed test starts over from the beginning
+func (m *Model) changeDifficulty(name string) error {
+	difficulty, err := core.ParseDifficulty(name)
+	if err != nil {
+		return err
+	}
+	if m.state != StateTyping || !inPractice(m) {
+		m.SetDifficulty(difficulty)
+		if m.state == StateTyping {
+			m.beginSession()
+		}
+		return nil
+	}
+	line := m.session.CurrentLineIndex()
+	m.SetDifficulty(difficulty)
+	m.restartAt(line)
+	return nil
+}
+
+// renderPalette renders the command palette and its suggestions
+func (m *Model) renderPalette() string {
+	p := m.palette
+	suggestions := m.paletteSuggestions()
+
+	rows := []string{m.theme.PaneTitle.Render("⌘ Command palette"), "> " + p.input + m.theme.Cursor.Render(" ")}
+
+	// Keep the selected row in view
+	first := 0
+	if p.selected >= paletteRows {
+		first = p.selected - paletteRows + 1
+	}
+	for i := first; i < len(suggestions) && i < first+paletteRows; i++ {
+		suggestion := suggestions[i]
+		row := fmt.Sprintf("  %-22s %s", suggestion.label, suggestion.description)
+		if i == p.selected {
+			row = m.theme.CurrentLine.Render("▶ " + row[2:])
+		} else {
+			row = m.theme.CodeLine.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	if len(suggestions) == 0 && p.input != "" {
+		rows = append(rows, m.theme.CodeLine.Render("  No matching commands"))
+	}
+	if p.err != "" {
+		rows = append(rows, m.theme.Error.Render("❌ "+p.err))
+	}
+	rows = append(rows, m.theme.Controls.Render("↑/↓: Select │ Tab: Complete │ Enter: Run │ Esc: Close"))
+
+	return m.theme.InputPane.Width(m.width - 4).Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// playlistRun tracks a playlist being typed
+type playlistRun struct {
+	playlist *core.Playlist
+	steps    []core.PlaylistEntry
+	contents []string             // Code of each step
+	results  []*core.SessionStats // Result of each step, nil until it is finished
+	current  int                  // Step being typed
+
+	// Settings outside the playlist, restored when it is left
+	pace     float64
+	keyboard bool
+}
+
+// finished reports whether every step has a result
+func (r *playlistRun) finished() bool {
+	for _, result := range r.results {
+		if result == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPlaylist loads every entry of a playlist and makes its first entry
+// the one to type. Entries' settings override the model's own until the
+// playlist is left.
+func (m *Model) SetPlaylist(playlist *core.Playlist) error {
+	run := &playlistRun{
+		playlist: playlist,
+		steps:    playlist.Steps(),
+		keyboard: m.showKeyboard,
+	}
+	if m.pacer != nil {
+		run.pace = m.pacer.TargetWPM()
+	}
+
+	for _, step := range run.steps {
+		content, err := step.Load(m.parser)
+		if err != nil {
+			return err
+		}
+		run.contents = append(run.contents, content)
+	}
+	run.results = make([]*core.SessionStats, len(run.steps))
+
+	m.playlist = run
+	return m.startPlaylistStep(0)
+}
+
+// startPlaylistStep loads a playlist step and applies its settings
+func (m *Model) startPlaylistStep(index int) error {
+	run := m.playlist
+	step := run.steps[index]
+	if err := m.loadContent(step.Path(), run.contents[index]); err != nil {
+		return err
+	}
+	run.current = index
+	m.filename = step.Name()
+
+	pace := run.pace
+	if step.Pace > 0 {
+		pace = float64(step.Pace)
+	}
+	m.SetPace(pace)
+
+	keyboard := run.keyboard
+	if step.Keyboard != nil {
+		keyboard = *step.Keyboard
+	}
+	m.SetShowKeyboard(keyboard)
+	return nil
+}
+
+// restartPlaylist clears a playlist's results and goes back to its first step
+func (m *Model) restartPlaylist() {
+	for i := range m.playlist.results {
+		m.playlist.results[i] = nil
+	}
+	if err := m.startPlaylistStep(0); err != nil {
+		m.message = "Could not restart playlist: " + err.Error()
+	}
+}
+
+// leavePlaylist stops typing a playlist and restores the settings it replaced
+func (m *Model) leavePlaylist() {
+	if m.playlist == nil {
+		return
+	}
+	m.SetPace(m.playlist.pace)
+	m.SetShowKeyboard(m.playlist.keyboard)
+	m.playlist = nil
+}
+
+// finishPlaylistStep records the result of the step just typed. A step
+// started over partway through is left without a result, so it comes
+// round again.
+func (m *Model) finishPlaylistStep() {
+	if m.playlist == nil {
+		return
+	}
+	if m.session.StartLine() > 0 {
+		m.message = "📝 This entry was started over partway through, so it does not count; type it again in full"
+		return
+	}
+	stats := m.session.Stats()
+	m.playlist.results[m.playlist.current] = &stats
+}
+
+// nextPlaylistStep starts typing the next step without a result
+func (m *Model) nextPlaylistStep() {
+	run := m.playlist
+	for i := range run.steps {
+		index := (run.current + 1 + i) % len(run.steps)
+		if run.results[index] != nil {
+			continue
+		}
+		if err := m.startPlaylistStep(index); err != nil {
+			m.message = "Could not load playlist entry: " + err.Error()
+			return
+		}
+		m.beginSession()
+		return
+	}
+}
+
+// handlePlaylistSummaryKeys handles the summary keys that move through a
+// playlist, reporting whether the key was one of them
+func (m *Model) handlePlaylistSummaryKeys(msg tea.KeyMsg) bool {
+	if m.playlist == nil {
+		return false
+	}
+
+	switch msg.String() {
+	case "enter", " ":
+		if m.playlist.finished() {
+			m.restartPlaylist()
+			m.state = StateWelcome
+		} else {
+			m.nextPlaylistStep()
+		}
+		return true
+	case "r":
+		if m.playlist.finished() {
+			m.restartPlaylist()
+			m.beginSession()
+			return true
+		}
+	}
+	return false
+}
+
+// playlistTitle labels the step being typed with its place in the playlist
+func (m *Model) playlistTitle() string {
+	return fmt.Sprintf("📋 %d/%d %s", m.playlist.current+1, len(m.playlist.steps), m.filename)
+}
+
+// renderPlaylistProgress returns the summary lines saying what comes next
+// in the playlist
+func (m *Model) renderPlaylistProgress() []string {
+	run := m.playlist
+	done := 0
+	for _, result := range run.results {
+		if result != nil {
+			done++
+		}
+	}
+
+	lines := []string{"", fmt.Sprintf("📋 Playlist %s: %d of %d done", run.playlist.Name, done, len(run.steps))}
+	for i := range run.steps {
+		index := (run.current + 1 + i) % len(run.steps)
+		if run.results[index] == nil {
+			lines = append(lines, "⏭️  Next: "+run.steps[index].Name())
+			break
+		}
+	}
+	return lines
+}
+
+// playlistControls returns the summary controls between playlist steps
+func (m *Model) playlistControls(reviewControl string) []string {
+	return []string{
+		"",
+		"What's next?",
+		"  Enter/Space - Next entry",
+		"  R - Retry this entry",
+		"  U - Open another file (leaves the playlist)",
+		reviewControl,
+		"  Q/Esc - Quit",
+	}
+}
+
+// stepWPM returns a step's speed in standard five-character words, so
+// steps in different languages compare
+func stepWPM(stats *core.SessionStats) float64 {
+	return core.StandardWPM(stats.TotalCharacters+stats.LinesCompleted, stats.TotalTime)
+}
+
+// renderPlaylistSummary renders the combined results of a finished
+// playlist with a breakdown by entry
+func (m *Model) renderPlaylistSummary() string {
+	run := m.playlist
+	title := m.theme.Title.Render("🎉 Playlist Complete: " + run.playlist.Name)
+
+	var totalTime time.Duration
+	var characters, typed, mistakes int
+	var weightedAccuracy float64
+	for _, result := range run.results {
+		totalTime += result.TotalTime
+		characters += result.TotalCharacters + result.LinesCompleted
+		typed += result.TotalCharacters
+		mistakes += result.TotalMistakes
+		weightedAccuracy += result.Accuracy * float64(result.TotalCharacters)
+	}
+	accuracy := 100.0
+	if typed > 0 {
+		accuracy = weightedAccuracy / float64(typed)
+	}
+
+	nameWidth := len("Entry")
+	for _, step := range run.steps {
+		nameWidth = max(nameWidth, lipgloss.Width(step.Name()))
+	}
+	nameWidth = min(nameWidth, 36)
+
+	stats := []string{
+		fmt.Sprintf("📋 Entries: %d", len(run.steps)),
+		fmt.Sprintf("⏱️  Total time: %s", formatDuration(totalTime)),
+		fmt.Sprintf("⚡ WPM: %.1f (5 characters per word)", core.StandardWPM(characters, totalTime)),
+		fmt.Sprintf("🎯 Accuracy: %.1f%%", accuracy),
+		fmt.Sprintf("❌ Total mistakes: %d", mistakes),
+		"",
+		"📊 By entry:",
+		fmt.Sprintf("   #  %s   Time    WPM   Accuracy  Mistakes", padRight("Entry", nameWidth)),
+	}
+
+	slowest, leastAccurate := 0, 0
+	for i, result := range run.results {
+		stats = append(stats, fmt.Sprintf("  %2d  %s  %s  %5.1f    %5.1f%%  %8d", i+1,
+			padRight(truncateWidth(run.steps[i].Name(), nameWidth), nameWidth),
+			formatDuration(result.TotalTime), stepWPM(result), result.Accuracy, result.TotalMistakes))
+
+		if stepWPM(result) < stepWPM(run.results[slowest]) {
+			slowest = i
+		}
+		if result.Accuracy < run.results[leastAccurate].Accuracy {
+			leastAccurate = i
+		}
+	}
+	if len(run.steps) > 1 {
+		stats = append(stats, "", fmt.Sprintf("🐢 Slowest: %s (%.1f WPM)", run.steps[slowest].Name(), stepWPM(run.results[slowest])))
+		if worst := run.results[leastAccurate]; worst.Accuracy < 100 {
+			stats = append(stats, fmt.Sprintf("🎯 Least accurate: %s (%.1f%%)", run.steps[leastAccurate].Name(), worst.Accuracy))
+		}
+	}
+
+	controls := []string{
+		"",
+		"What's next?",
+		"  R - Run the playlist again",
+		"  U - Open another file",
+		"  Enter/Space - Back to menu",
+		"  Q/Esc - Quit",
+	}
+
+	styledStats := m.theme.Summary.Render(strings.Join(stats, "\n"))
+	styledControls := m.theme.Text.Render(strings.Join(controls, "\n"))
+	if m.message != "" {
+		message := m.theme.Error.Render(m.message)
+		return lipgloss.JoinVertical(lipgloss.Center, title, "", styledStats, styledControls, "", message)
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, title, "", styledStats, styledControls)
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/theme"
+)
+
+// RenderThemePreview renders a sample of every part of the UI in a theme
+func RenderThemePreview(name string, t *theme.Theme) string {
+	title := t.Title.Render("🎨 Theme: " + name)
+	header := t.Header.Render("📁 sample.go • Progress: 3/12 (16.7%)")
+
+	// A code pane with a completed line, the current line and an upcoming line
+	completed := "  1 │ " + t.CorrectChar.Render("func sum(") + t.IncorrectChar.Render("n") +
+		t.CorrectChar.Render("ums []int) int {")
+	current := t.CurrentLine.Render("  2 │ " + t.RemainingChar.Render("    ") +
+		t.CorrectChar.Render("total ") + t.IncorrectChar.Render(":") +
+		t.Cursor.Render("=") + t.RemainingChar.Render(" 0") + t.ExtraChar.Render("x"))
+	upcoming := t.CodeLine.Render("  3 │     ") + t.PaceMarker.Render("r") + t.CodeLine.Render("eturn total")
+	codePane := lipgloss.JoinVertical(
+		lipgloss.Left,
+		t.PaneTitle.Render("📖 Code Practice"),
+		t.CodePane.Render(strings.Join([]string{completed, current, upcoming}, "\n")),
+	)
+
+	statuses := []struct {
+		status core.PowerStatus
+		label  string
+	}{
+		{core.PowerStatusZenMode, "🧘 Zen Mode"},
+		{core.PowerStatusFullPower, "💪 Full Power"},
+		{core.PowerStatusGoodFlow, "⚡ Good Flow"},
+		{core.PowerStatusFatigue, "💤 Fatigue"},
+		{core.PowerStatusBurnout, "🔥 Rest Needed"},
+	}
+	var power []string
+	for _, s := range statuses {
+		power = append(power, powerStatusStyle(t, s.status).Render(s.label))
+	}
+
+	metrics := t.MetricsPanel.Render(fmt.Sprintf("⏱️  Time: %s │ 🎯 Accuracy: %.1f%% │ ⚡ WPM: %d", "01:23", 96.4, 54))
+	chart := RenderLineChart(t, []float64{30, 42, 38, 51, 47, 58, 55, 63}, 24, 2)
+	summary := t.Summary.Render("🎉 Session summary")
+	keys := t.Key.Render(" a ") + " " + t.KeyNext.Render(" s ") + " " + t.KeyMistake.Render(" d ") + " " +
+		t.KeyHeatLow.Render(" f ") + " " + t.KeyHeatMid.Render(" g ") + " " + t.KeyHeatHigh.Render(" h ")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		header,
+		"",
+		codePane,
+		"",
+		strings.Join(power, " │ "),
+		metrics,
+		"",
+		keys,
+		"",
+		chart,
+		"",
+		summary,
+		t.Error.Render("❌ Error message"),
+		t.Controls.Render("Ctrl+R: Retry │ Ctrl+U: Upload │ Esc: Menu"),
+		t.Text.Render("Regular text"),
+	)
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// maxQuickFiles is how many files the welcome screen lists, one per digit key
+const maxQuickFiles = 9
+
+// quickFile is a starred or recent file listed on the welcome screen
+type quickFile struct {
+	path    string
+	starred bool
+	best    core.FileBest // Zero if the file was never practiced
+}
+
+// recentFiles returns up to limit recently opened files that still exist,
+// newest first. Files practiced before the recent list was kept are taken
+// from the history.
+func (m *Model) recentFiles(records []core.SessionRecord, limit int) []string {
+	var candidates []string
+	if m.files != nil {
+		candidates = append(candidates, m.files.Recent...)
+	}
+	candidates = append(candidates, core.RecentFiles(records, limit*2)...)
+
+	var recent []string
+	seen := make(map[string]bool)
+	for _, path := range candidates {
+		if len(recent) == limit {
+			break
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Stat(path); err == nil {
+			recent = append(recent, path)
+		}
+	}
+	return recent
+}
+
+// historyRecords loads the session history, or nothing if there is none
+func (m *Model) historyRecords() []core.SessionRecord {
+	if m.history == nil {
+		return nil
+	}
+	records, err := m.history.Load()
+	if err != nil {
+		return nil
+	}
+	return records
+}
+
+// quickFileList returns the starred files followed by the recent ones,
+// loading them on first use after a change
+func (m *Model) quickFileList() []quickFile {
+	if m.quickFilesLoaded {
+		return m.quickFiles
+	}
+	m.quickFilesLoaded = true
+	m.quickFiles = nil
+
+	records := m.historyRecords()
+	bests := core.FileBests(records, m.session.WPMDefinition(), m.session.Difficulty())
+
+	var starred []string
+	if m.files != nil {
+		starred = m.files.Starred
+	}
+	seen := make(map[string]bool)
+	for _, path := range starred {
+		if _, err := os.Stat(path); err == nil && len(m.quickFiles) < maxQuickFiles {
+			m.quickFiles = append(m.quickFiles, quickFile{path: path, starred: true, best: bests[path]})
+			seen[path] = true
+		}
+	}
+	for _, path := range m.recentFiles(records, maxQuickFiles) {
+		if !seen[path] && len(m.quickFiles) < maxQuickFiles {
+			m.quickFiles = append(m.quickFiles, quickFile{path: path, best: bests[path]})
+		}
+	}
+	return m.quickFiles
+}
+
+// invalidateQuickFiles makes the welcome screen reload its file list
+func (m *Model) invalidateQuickFiles() {
+	m.quickFilesLoaded = false
+}
+
+// rememberFile puts a file at the top of the recent list
+func (m *Model) rememberFile(path string) {
+	m.invalidateQuickFiles()
+	if m.files == nil {
+		return
+	}
+	m.files.Touch(path)
+	if err := m.files.Save(); err != nil {
+		m.message = "Could not save recent files: " + err.Error()
+	}
+}
+
+// toggleStar stars or unstars the current file
+func (m *Model) toggleStar() {
+	if m.files == nil {
+		return
+	}
+	starred := m.files.ToggleStar(m.filePath)
+	m.invalidateQuickFiles()
+	if err := m.files.Save(); err != nil {
+		m.message = "Could not save starred files: " + err.Error()
+		return
+	}
+	if starred {
+		m.message = "⭐ Starred " + m.filename
+	} else {
+		m.message = "Removed star from " + m.filename
+	}
+}
+
+// launchQuickFile loads the nth listed file and starts practicing it
+func (m *Model) launchQuickFile(n int) {
+	files := m.quickFileList()
+	if n < 1 || n > len(files) {
+		return
+	}
+	if err := m.LoadFile(files[n-1].path); err != nil {
+		m.message = "Could not open " + filepath.Base(files[n-1].path) + ": " + err.Error()
+		return
+	}
+	m.beginSession()
+}
+
+// renderQuickFiles renders the numbered list of starred and recent files
+// with their personal bests, or nothing if there are none
+func (m *Model) renderQuickFiles() []string {
+	files := m.quickFileList()
+	if len(files) == 0 {
+		return nil
+	}
+
+	nameWidth, dirWidth := 0, 0
+	for _, file := range files {
+		nameWidth = max(nameWidth, lipgloss.Width(filepath.Base(file.path)))
+		dirWidth = max(dirWidth, lipgloss.Width(shortenPath(filepath.Dir(file.path))))
+	}
+	nameWidth = min(nameWidth, 24)
+	dirWidth = min(dirWidth, 28)
+
+	lines := []string{"📌 Your files:"}
+	for i, file := range files {
+		icon := "🕘"
+		if file.starred {
+			icon = "⭐"
+		}
+		name := truncateWidth(filepath.Base(file.path), nameWidth)
+		dir := truncateWidth(shortenPath(filepath.Dir(file.path)), dirWidth)
+
+		bests := "not practiced yet"
+		if file.best.Sessions > 0 {
+			bests = fmt.Sprintf("🏆 %5.1f WPM │ %5.1f%% │ %s", file.best.BestWPM, file.best.BestAccuracy,
+				file.best.LastPracticed.Format("2006-01-02"))
+		}
+		lines = append(lines, fmt.Sprintf("  %d %s %s  %s  %s", i+1, icon,
+			padRight(name, nameWidth), padRight(dir, dirWidth), bests))
+	}
+	return lines
+}
+
+// padRight pads text with spaces to width
+func padRight(text string, width int) string {
+	if n := width - lipgloss.Width(text); n > 0 {
+		return text + strings.Repeat(" ", n)
+	}
+	return text
+}
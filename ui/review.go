@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+// withTypedRow returns the rows for a code line, adding the typed characters
+// underneath when the "below" error style is active
+func (m *Model) withTypedRow(styledLine, code, typed string) []string {
+	if m.errorStyle != ErrorStyleBelow {
+		return []string{styledLine}
+	}
+	row := m.renderTypedRow(code, typed)
+	if row == "" {
+		return []string{styledLine}
+	}
+	return []string{styledLine, row}
+}
+
+// splitLine splits a line of code into the indentation that is skipped
+// for the typist and the text that is typed
+func (m *Model) splitLine(code string) (string, string) {
+	typed := m.session.Difficulty().TypedText(code)
+	return code[:len(code)-len(typed)], typed
+}
+
+// renderTypedRow renders what the user typed at each mistyped position of a
+// line, aligned under the expected characters. It returns an empty string
+// when the line has no mistakes so far.
+func (m *Model) renderTypedRow(code, typed string) string {
+	indentation, trimmed := m.splitLine(code)
+
+	var row strings.Builder
+	row.WriteString("    │ ")
+	row.WriteString(indentation)
+
+	mistakes := 0
+	expected, input := []rune(trimmed), []rune(typed)
+	for i := 0; i < len(expected) && i < len(input); i++ {
+		if input[i] == expected[i] {
+			row.WriteString(" ")
+			continue
+		}
+		row.WriteString(m.theme.ExtraChar.Render(visibleChar(input[i])))
+		mistakes++
+	}
+
+	if mistakes == 0 {
+		return ""
+	}
+	return strings.TrimRight(row.String(), " ")
+}
+
+// renderLineReview renders a diff of expected and typed text for every line
+// that was typed with mistakes
+func (m *Model) renderLineReview() string {
+	lineStats := m.session.LineStats()
+
+	var reviewed []core.LineStats
+	for _, line := range lineStats {
+		if line.Mistakes > 0 {
+			reviewed = append(reviewed, line)
+		}
+	}
+
+	if len(reviewed) == 0 {
+		return m.theme.Summary.Render("✨ No mistakes to review - every line was typed perfectly!")
+	}
+
+	// Each entry takes two rows; keep the review on one screen
+	limit := 8
+	if m.height > 0 {
+		limit = (m.height - 16) / 2
+		if limit < 3 {
+			limit = 3
+		}
+	}
+
+	rows := []string{
+		fmt.Sprintf("🔍 LINE REVIEW: %d of %d lines had mistakes", len(reviewed), len(lineStats)),
+		"",
+	}
+	for i, line := range reviewed {
+		if i == limit {
+			rows = append(rows, "", fmt.Sprintf("… and %d more lines", len(reviewed)-limit))
+			break
+		}
+		expected, typed := m.renderLineDiff(line.Original, line.UserInput)
+		rows = append(rows,
+			fmt.Sprintf("%3d - %s", line.Line+1, expected),
+			fmt.Sprintf("    + %s", typed),
+		)
+	}
+
+	return m.theme.Summary.Render(strings.Join(rows, "\n"))
+}
+
+// renderLineDiff renders the expected and typed text of a line with the
+// differing characters marked
+func (m *Model) renderLineDiff(expectedText, typedText string) (string, string) {
+	var expectedRow, typedRow strings.Builder
+	expected, typed := []rune(expectedText), []rune(typedText)
+
+	for i := 0; i < len(expected); i++ {
+		if i < len(typed) && typed[i] == expected[i] {
+			expectedRow.WriteRune(expected[i])
+		} else {
+			expectedRow.WriteString(m.theme.IncorrectChar.Render(string(expected[i])))
+		}
+	}
+
+	for i := 0; i < len(typed); i++ {
+		if i < len(expected) && typed[i] == expected[i] {
+			typedRow.WriteRune(typed[i])
+		} else {
+			typedRow.WriteString(m.theme.ExtraChar.Render(visibleChar(typed[i])))
+		}
+	}
+
+	return expectedRow.String(), typedRow.String()
+}
+
+// hotspotLines is how many lines the summary lists as slowest and most
+// error-prone
+const hotspotLines = 3
+
+// renderLineHotspots returns the summary rows listing the slowest and most
+// error-prone lines, or nil if there is nothing to show
+func (m *Model) renderLineHotspots() []string {
+	lineStats := m.session.LineStats()
+	slowest := core.SlowestLines(lineStats, hotspotLines)
+	errorProne := core.ErrorProneLines(lineStats, hotspotLines)
+
+	var rows []string
+	if len(slowest) > 0 {
+		rows = append(rows, "", "🐢 SLOWEST LINES:")
+		for _, line := range slowest {
+			rows = append(rows, fmt.Sprintf("%4d │ %5.1f WPM │ %5.1fs, %4.1fs to first key │ %s",
+				line.Line+1, line.WPM, line.TimeSpent.Seconds(), line.Hesitation.Seconds(), shortenCode(line.Original)))
+		}
+	}
+	if len(errorProne) > 0 {
+		rows = append(rows, "", "🎯 MOST ERROR-PRONE LINES:")
+		for _, line := range errorProne {
+			rows = append(rows, fmt.Sprintf("%4d │ %2d mistakes │ %5.1f%% accuracy │ %s",
+				line.Line+1, line.Mistakes, line.Accuracy, shortenCode(line.Original)))
+		}
+	}
+	return rows
+}
+
+// shortenCode trims a line of code to fit a summary row
+func shortenCode(code string) string {
+	const maxLen = 36
+	runes := []rune(code)
+	if len(runes) <= maxLen {
+		return code
+	}
+	return string(runes[:maxLen-1]) + "…"
+}
@@ -2,14 +2,23 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vamshi1188/SyntaxRush/core"
+	"github.com/vamshi1188/SyntaxRush/theme"
 )
 
 // renderWelcome renders the welcome screen
 func (m *Model) renderWelcome() string {
+	current := "📁 Current file: " + m.filename
+	if m.files != nil && m.files.IsStarred(m.filePath) {
+		current += " ⭐"
+	}
+
 	instructions := []string{
 		"",
 		"🚀 SyntaxRush - Welcome! 🚀",
@@ -18,16 +27,44 @@ func (m *Model) renderWelcome() string {
 		"Practice typing real code. Master syntax.",
 		"🔊 Audio feedback for mistakes and success!",
 		"",
-		"📁 Current file: " + m.filename,
-		fmt.Sprintf("📄 Lines: %d", m.totalLines),
+		current,
+		fmt.Sprintf("📄 Lines: %d", m.session.LineCount()),
 		"",
+	}
+	if m.playlist != nil {
+		instructions = append(instructions[:len(instructions)-3],
+			fmt.Sprintf("📋 Playlist: %s (%d entries)", m.playlist.playlist.Name, len(m.playlist.steps)),
+			"📁 First entry: "+m.filename,
+			fmt.Sprintf("📄 Lines: %d", m.session.LineCount()),
+			"",
+		)
+	}
+
+	quickFiles := m.renderQuickFiles()
+	controls := []string{
 		"Controls:",
 		"  Enter/Space - Start typing practice",
-		"  Ctrl+U      - Upload new file",
+	}
+	if len(quickFiles) > 0 {
+		controls = append(controls, "  1-9         - Practice one of your files")
+	}
+	if key := m.keys[core.ActionOpen]; key != "" {
+		controls = append(controls, fmt.Sprintf("  %-11s - Open another file", keyLabel(key)))
+	}
+	if m.files != nil {
+		controls = append(controls, "  F           - Star or unstar the current file")
+	}
+	controls = append(controls,
 		"  Q/Esc       - Quit",
 		"",
 		"Press Enter or Space to begin!",
+	)
+
+	if len(quickFiles) > 0 {
+		instructions = append(instructions, quickFiles...)
+		instructions = append(instructions, "")
 	}
+	instructions = append(instructions, controls...)
 
 	content := strings.Join(instructions, "\n")
 	styledContent := m.theme.Text.Render(content)
@@ -57,28 +94,44 @@ func (m *Model) renderTyping() string {
 	// Controls help
 	controls := m.renderControls()
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		"",
-		codePane,
-		"",
-		mpiPanel,
-		"",
-		metricsPanel,
-		"",
-		controls,
-	)
+	sections := []string{header, "", codePane, "", mpiPanel}
+	if m.showPowerGraph {
+		sections = append(sections, m.renderPowerGraph())
+	}
+	sections = append(sections, "", metricsPanel)
+	if m.showKeyboard {
+		sections = append(sections, "", m.renderKeyboard())
+	}
+	sections = append(sections, "", controls)
+	if m.message != "" {
+		sections = append(sections, m.theme.Error.Render(m.message))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
 // renderHeader renders the file information header
 func (m *Model) renderHeader() string {
-	progress := fmt.Sprintf("%d/%d", m.currentLine+1, m.totalLines)
-	percentage := float64(m.currentLine) / float64(m.totalLines) * 100
+	currentLine := m.session.CurrentLineIndex()
+	totalLines := m.session.LineCount()
+	progress := fmt.Sprintf("%d/%d", currentLine+1, totalLines)
+	percentage := float64(currentLine) / float64(totalLines) * 100
 
 	title := fmt.Sprintf("📁 %s", m.filename)
+	if m.redoPass > 0 {
+		title += fmt.Sprintf(" • 🔁 Mistakes pass %d", m.redoPass)
+	}
+	if m.playlist != nil {
+		title = m.playlistTitle()
+	}
 	progressInfo := fmt.Sprintf("Progress: %s (%.1f%%)", progress, percentage)
 
+	// Timed tests have no fixed end line; show the countdown instead
+	if limit := m.session.TimeLimit(); limit > 0 {
+		remaining := m.session.Remaining().Round(time.Second)
+		progressInfo = fmt.Sprintf("⏳ %s test: %s left", formatSeconds(limit), formatSeconds(remaining))
+	}
+
 	headerStyle := m.theme.Header.Width(m.width - 2)
 	return headerStyle.Render(fmt.Sprintf("%s • %s", title, progressInfo))
 }
@@ -88,17 +141,18 @@ func (m *Model) renderCodePane() string {
 	var lines []string
 
 	// Calculate visible range
+	codeLines := m.session.Lines()
 	startLine := m.viewportStart
 	endLine := startLine + m.maxViewLines
-	if endLine > m.totalLines {
-		endLine = m.totalLines
+	if endLine > len(codeLines) {
+		endLine = len(codeLines)
 	}
 
 	for i := startLine; i < endLine; i++ {
 		lineNum := fmt.Sprintf("%3d", i+1)
-		code := m.codeLines[i]
+		code := codeLines[i]
 
-		if i == m.currentLine {
+		if i == m.session.CurrentLineIndex() {
 			// Highlight current line
 			styledLine := m.theme.CurrentLine.Render(fmt.Sprintf("%s │ %s", lineNum, code))
 			lines = append(lines, styledLine)
@@ -118,8 +172,9 @@ func (m *Model) renderCodePane() string {
 
 // renderInputPane renders the typing input area
 func (m *Model) renderInputPane() string {
-	currentCodeRaw := m.getCurrentLineRaw()                 // Original line with indentation
-	currentCode := m.getCurrentLine()                       // Trimmed line for typing
+	currentCodeRaw := m.session.CurrentLineRaw() // Original line with indentation
+	currentCode := m.session.CurrentLine()       // Trimmed line for typing
+	userInput := m.session.Input()
 	leadingSpaces := len(currentCodeRaw) - len(currentCode) // Calculate indentation
 
 	// Create styled input showing correct/incorrect characters
@@ -132,17 +187,18 @@ func (m *Model) renderInputPane() string {
 	}
 
 	// Then handle the actual typing part (trimmed content)
-	for i, char := range currentCode {
-		if i < len(m.userInput) {
-			userChar := rune(m.userInput[i])
+	typed, code := []rune(userInput), []rune(currentCode)
+	for i, char := range code {
+		if i < len(typed) {
+			userChar := typed[i]
 			if userChar == char {
 				// Correct character
 				styledInput.WriteString(m.theme.CorrectChar.Render(string(char)))
 			} else {
 				// Incorrect character
-				styledInput.WriteString(m.theme.IncorrectChar.Render(string(char)))
+				styledInput.WriteString(m.renderMistake(char, userChar))
 			}
-		} else if i == len(m.userInput) {
+		} else if i == len(typed) {
 			// Current cursor position
 			styledInput.WriteString(m.theme.Cursor.Render(string(char)))
 		} else {
@@ -152,8 +208,8 @@ func (m *Model) renderInputPane() string {
 	}
 
 	// Show extra characters if user typed too much
-	if len(m.userInput) > len(currentCode) {
-		extra := m.userInput[len(currentCode):]
+	if len(typed) > len(code) {
+		extra := string(typed[len(code):])
 		styledInput.WriteString(m.theme.ExtraChar.Render(extra))
 	}
 
@@ -175,31 +231,65 @@ func (m *Model) renderUnifiedCodePane() string {
 	var lines []string
 
 	// Calculate visible range
+	codeLines := m.session.Lines()
 	startLine := m.viewportStart
 	endLine := startLine + m.maxViewLines
-	if endLine > m.totalLines {
-		endLine = m.totalLines
+	if endLine > len(codeLines) {
+		endLine = len(codeLines)
 	}
 
+	// Each line is a block of one row, or two when typed characters are
+	// shown under it
+	var blocks [][]string
+	currentBlock := -1
+	paceLine, paceCol := m.pacePosition()
 	for i := startLine; i < endLine; i++ {
 		lineNum := fmt.Sprintf("%3d", i+1)
-		code := m.codeLines[i]
+		code := codeLines[i]
 
-		if i == m.currentLine {
+		// Column of the pace marker on this line, or -1
+		markerCol := -1
+		if i == paceLine {
+			markerCol = paceCol
+		}
+
+		if i == m.session.CurrentLineIndex() {
 			// This is the current line being typed - show typing progress
-			styledLine := m.renderCurrentLineWithTyping(lineNum, code)
-			lines = append(lines, styledLine)
-		} else if userInput, isCompleted := m.completedLines[i]; isCompleted {
+			styledLine := m.renderCurrentLineWithTyping(lineNum, code, markerCol)
+			currentBlock = len(blocks)
+			blocks = append(blocks, m.withTypedRow(styledLine, code, m.session.Input()))
+		} else if userInput, isCompleted := m.session.CompletedInput(i); isCompleted {
 			// This line was completed - show it with color coding
-			styledLine := m.renderCompletedLineWithColors(lineNum, code, userInput)
-			lines = append(lines, styledLine)
+			styledLine := m.renderCompletedLineWithColors(lineNum, code, userInput, markerCol)
+			blocks = append(blocks, m.withTypedRow(styledLine, code, userInput))
+		} else if i >= m.session.StartLine() && i < m.session.CurrentLineIndex() && !m.session.LeftOut(i) {
+			// Passed without being completed, so the line was skipped
+			blocks = append(blocks, []string{m.theme.CodeLine.Render(fmt.Sprintf("%s │ %s", lineNum, code)) + m.theme.RemainingChar.Render(" ⏭")})
 		} else {
 			// Regular line display (not yet reached)
-			styledLine := m.theme.CodeLine.Render(fmt.Sprintf("%s │ %s", lineNum, code))
-			lines = append(lines, styledLine)
+			blocks = append(blocks, []string{m.renderUpcomingLine(lineNum, code, markerCol)})
 		}
 	}
 
+	// Extra rows can overflow the pane; drop lines from the top until the
+	// current line fits, then from the bottom
+	rows := 0
+	for _, block := range blocks {
+		rows += len(block)
+	}
+	for rows > m.maxViewLines && currentBlock > 0 {
+		rows -= len(blocks[0])
+		blocks = blocks[1:]
+		currentBlock--
+	}
+	for rows > m.maxViewLines && len(blocks) > 1 {
+		rows -= len(blocks[len(blocks)-1])
+		blocks = blocks[:len(blocks)-1]
+	}
+	for _, block := range blocks {
+		lines = append(lines, block...)
+	}
+
 	content := strings.Join(lines, "\n")
 
 	title := m.theme.PaneTitle.Render("📖 Code Practice")
@@ -208,10 +298,25 @@ func (m *Model) renderUnifiedCodePane() string {
 	return lipgloss.JoinVertical(lipgloss.Left, title, paneStyle.Render(content))
 }
 
+// renderUpcomingLine renders a line that has not been reached yet
+func (m *Model) renderUpcomingLine(lineNum, code string, markerCol int) string {
+	chars := []rune(code)
+	indentation, _ := m.splitLine(code)
+	marker := utf8.RuneCountInString(indentation) + markerCol
+	if markerCol < 0 || marker >= len(chars) {
+		return m.theme.CodeLine.Render(fmt.Sprintf("%s │ %s", lineNum, code))
+	}
+
+	return m.theme.CodeLine.Render(fmt.Sprintf("%s │ %s", lineNum, string(chars[:marker]))) +
+		m.theme.PaceMarker.Render(string(chars[marker])) +
+		m.theme.CodeLine.Render(string(chars[marker+1:]))
+}
+
 // renderCurrentLineWithTyping renders the current line with typing progress
-func (m *Model) renderCurrentLineWithTyping(lineNum, codeLine string) string {
-	currentCodeRaw := codeLine                              // Original line with indentation
-	currentCode := m.getCurrentLine()                       // Trimmed line for typing
+func (m *Model) renderCurrentLineWithTyping(lineNum, codeLine string, markerCol int) string {
+	currentCodeRaw := codeLine             // Original line with indentation
+	currentCode := m.session.CurrentLine() // Trimmed line for typing
+	userInput := m.session.Input()
 	leadingSpaces := len(currentCodeRaw) - len(currentCode) // Calculate indentation
 
 	// Build the display line: line number + separator + styled content
@@ -226,19 +331,25 @@ func (m *Model) renderCurrentLineWithTyping(lineNum, codeLine string) string {
 	}
 
 	// Now handle the actual typing content
-	for i, char := range currentCode {
-		if i < len(m.userInput) {
-			userChar := rune(m.userInput[i])
-			if userChar == char {
+	typed, code := []rune(userInput), []rune(currentCode)
+	for i, char := range code {
+		if i < len(typed) {
+			userChar := typed[i]
+			if userChar != char {
+				// Incorrect character - show the expected char in error style
+				lineBuilder.WriteString(m.renderMistake(char, userChar))
+			} else if i == markerCol {
+				lineBuilder.WriteString(m.theme.PaceMarker.Render(string(char)))
+			} else {
 				// Correct character
 				lineBuilder.WriteString(m.theme.CorrectChar.Render(string(char)))
-			} else {
-				// Incorrect character - show the expected char in error style
-				lineBuilder.WriteString(m.theme.IncorrectChar.Render(string(char)))
 			}
-		} else if i == len(m.userInput) {
+		} else if i == len(typed) {
 			// Current cursor position
 			lineBuilder.WriteString(m.theme.Cursor.Render(string(char)))
+		} else if i == markerCol {
+			// Where the target pace is
+			lineBuilder.WriteString(m.theme.PaceMarker.Render(string(char)))
 		} else {
 			// Remaining characters
 			lineBuilder.WriteString(m.theme.RemainingChar.Render(string(char)))
@@ -246,13 +357,13 @@ func (m *Model) renderCurrentLineWithTyping(lineNum, codeLine string) string {
 	}
 
 	// Show extra characters if user typed too much
-	if len(m.userInput) > len(currentCode) {
-		extra := m.userInput[len(currentCode):]
+	if len(typed) > len(code) {
+		extra := string(typed[len(code):])
 		lineBuilder.WriteString(m.theme.ExtraChar.Render(extra))
 	}
 
 	// Show cursor if at end of line
-	if len(m.userInput) == len(currentCode) {
+	if len(typed) == len(code) {
 		lineBuilder.WriteString(m.theme.Cursor.Render("█"))
 	}
 
@@ -261,10 +372,9 @@ func (m *Model) renderCurrentLineWithTyping(lineNum, codeLine string) string {
 }
 
 // renderCompletedLineWithColors renders a completed line with color feedback
-func (m *Model) renderCompletedLineWithColors(lineNum, originalCode, userInput string) string {
-	// We need to get the trimmed version of the original code for comparison
-	trimmedCode := strings.TrimLeft(originalCode, " \t")
-	leadingSpaces := len(originalCode) - len(trimmedCode)
+func (m *Model) renderCompletedLineWithColors(lineNum, originalCode, userInput string, markerCol int) string {
+	// Only the typed part of the line is compared
+	indentation, trimmedCode := m.splitLine(originalCode)
 
 	// Build the display line: line number + separator + styled content
 	var lineBuilder strings.Builder
@@ -272,36 +382,38 @@ func (m *Model) renderCompletedLineWithColors(lineNum, originalCode, userInput s
 	lineBuilder.WriteString(" │ ")
 
 	// Add the leading spaces (show them as dim/gray)
-	if leadingSpaces > 0 {
-		indentation := originalCode[:leadingSpaces]
+	if indentation != "" {
 		lineBuilder.WriteString(m.theme.RemainingChar.Render(indentation))
 	}
 
 	// Compare user input with the trimmed code and style accordingly
-	maxLen := len(trimmedCode)
-	if len(userInput) > maxLen {
-		maxLen = len(userInput)
+	typed, code := []rune(userInput), []rune(trimmedCode)
+	maxLen := len(code)
+	if len(typed) > maxLen {
+		maxLen = len(typed)
 	}
 
 	for i := 0; i < maxLen; i++ {
-		if i < len(userInput) && i < len(trimmedCode) {
+		if i < len(typed) && i < len(code) {
 			// Character exists in both user input and expected code
-			userChar := rune(userInput[i])
-			expectedChar := rune(trimmedCode[i])
+			userChar := typed[i]
+			expectedChar := code[i]
 
-			if userChar == expectedChar {
+			if userChar == expectedChar && i == markerCol {
+				lineBuilder.WriteString(m.theme.PaceMarker.Render(string(expectedChar)))
+			} else if userChar == expectedChar {
 				// Correct character - green
 				lineBuilder.WriteString(m.theme.CorrectChar.Render(string(expectedChar)))
 			} else {
-				// Incorrect character - red (show the expected character)
-				lineBuilder.WriteString(m.theme.IncorrectChar.Render(string(expectedChar)))
+				// Incorrect character - show the expected character in error style
+				lineBuilder.WriteString(m.renderMistake(expectedChar, userChar))
 			}
-		} else if i < len(trimmedCode) {
+		} else if i < len(code) {
 			// User didn't type this character - show it as missing/gray
-			lineBuilder.WriteString(m.theme.RemainingChar.Render(string(trimmedCode[i])))
+			lineBuilder.WriteString(m.theme.RemainingChar.Render(string(code[i])))
 		} else {
 			// User typed extra characters - show them as extra/error
-			lineBuilder.WriteString(m.theme.ExtraChar.Render(string(userInput[i])))
+			lineBuilder.WriteString(m.theme.ExtraChar.Render(string(typed[i])))
 		}
 	}
 
@@ -309,13 +421,42 @@ func (m *Model) renderCompletedLineWithColors(lineNum, originalCode, userInput s
 	return m.theme.CodeLine.Render(lineBuilder.String())
 }
 
+// renderMistake renders an expected character the user mistyped, marked
+// according to the configured error style
+func (m *Model) renderMistake(expected, typed rune) string {
+	switch m.errorStyle {
+	case ErrorStyleUnderline:
+		return m.theme.IncorrectChar.Underline(true).Render(string(expected))
+	case ErrorStyleInverse:
+		return m.theme.IncorrectChar.Reverse(true).Render(string(expected))
+	case ErrorStyleTyped:
+		return m.theme.IncorrectChar.Render(string(expected)) +
+			m.theme.ExtraChar.Render(visibleChar(typed))
+	default:
+		return m.theme.IncorrectChar.Render(string(expected))
+	}
+}
+
+// visibleChar returns a printable stand-in for whitespace characters
+func visibleChar(char rune) string {
+	switch char {
+	case ' ':
+		return "␣"
+	case '\t':
+		return "→"
+	default:
+		return string(char)
+	}
+}
+
 // renderMusclePowerIndicator renders the muscle power indicator panel
 func (m *Model) renderMusclePowerIndicator() string {
-	powerLevel := m.mpi.GetCurrentPowerLevel()
-	stats := m.mpi.GetStats()
+	mpi := m.session.MPI()
+	powerLevel := mpi.GetCurrentPowerLevel()
+	stats := mpi.GetStats()
 
 	// Create power bar
-	powerBar := m.mpi.GetPowerBar(20)
+	powerBar := mpi.GetPowerBar(20)
 
 	// Build MPI display
 	mpiInfo := []string{
@@ -344,79 +485,260 @@ func (m *Model) renderMusclePowerIndicator() string {
 	content := strings.Join(mpiInfo, " │ ")
 
 	// Style based on power level
-	var styledContent string
-	switch powerLevel.Status {
-	case 0: // ZenMode
-		styledContent = m.theme.Text.Foreground(lipgloss.Color("13")).Render(content) // Purple
-	case 1: // FullPower
-		styledContent = m.theme.Text.Foreground(lipgloss.Color("10")).Render(content) // Green
-	case 2: // GoodFlow
-		styledContent = m.theme.Text.Foreground(lipgloss.Color("12")).Render(content) // Blue
-	case 3: // Fatigue
-		styledContent = m.theme.Text.Foreground(lipgloss.Color("11")).Render(content) // Yellow
-	case 4: // Burnout
-		styledContent = m.theme.Text.Foreground(lipgloss.Color("9")).Render(content) // Red
+	styledContent := m.powerStatusStyle(powerLevel.Status).Render(content)
+
+	return m.theme.MetricsPanel.Width(m.width - 2).Render(styledContent)
+}
+
+// powerStatusStyle returns the text style for a power status
+func (m *Model) powerStatusStyle(status core.PowerStatus) lipgloss.Style {
+	return powerStatusStyle(m.theme, status)
+}
+
+// powerStatusStyle returns the theme's style for a power status
+func powerStatusStyle(t *theme.Theme, status core.PowerStatus) lipgloss.Style {
+	switch status {
+	case core.PowerStatusZenMode:
+		return t.PowerZen
+	case core.PowerStatusFullPower:
+		return t.PowerFull
+	case core.PowerStatusGoodFlow:
+		return t.PowerFlow
+	case core.PowerStatusFatigue:
+		return t.PowerFatigue
+	case core.PowerStatusBurnout:
+		return t.PowerBurnout
 	default:
-		styledContent = m.theme.Text.Render(content)
+		return t.Text
 	}
+}
 
-	return m.theme.MetricsPanel.Width(m.width - 2).Render(styledContent)
+// renderPowerGraph renders a live sparkline of recent power, colored by status
+func (m *Model) renderPowerGraph() string {
+	history := m.session.MPI().GetPowerHistory()
+
+	// Show as many recent snapshots as fit next to the label
+	label := "📈 Power: "
+	maxPoints := m.width - 2 - lipgloss.Width(label) - 2
+	if maxPoints < 10 {
+		maxPoints = 10
+	}
+	if len(history) > maxPoints {
+		history = history[len(history)-maxPoints:]
+	}
+
+	if len(history) == 0 {
+		return m.theme.MetricsPanel.Width(m.width - 2).Render(label + "waiting for keystrokes…")
+	}
+
+	values := make([]float64, len(history))
+	for i, snapshot := range history {
+		values[i] = snapshot.Power
+	}
+
+	var graph strings.Builder
+	for i, block := range []rune(Sparkline(values)) {
+		graph.WriteString(m.powerStatusStyle(history[i].Status).Render(string(block)))
+	}
+
+	return m.theme.MetricsPanel.Width(m.width - 2).Render(label + graph.String())
+}
+
+// renderPowerChart renders the full power-over-time chart for the summary,
+// with a band underneath colored by the power status at each point
+func (m *Model) renderPowerChart() string {
+	history := m.session.MPI().GetPowerHistory()
+	if len(history) < 2 {
+		return ""
+	}
+
+	width := 60
+	if m.width > 0 && m.width-20 < width {
+		width = m.width - 20
+	}
+	if width < 10 {
+		width = 10
+	}
+
+	values := make([]float64, len(history))
+	for i, snapshot := range history {
+		values[i] = snapshot.Power
+	}
+
+	// Status band: one block per chart column, sampled like the chart itself
+	var band strings.Builder
+	for col := 0; col < width; col++ {
+		idx := 0
+		if width > 1 {
+			idx = int(math.Round(float64(col) * float64(len(history)-1) / float64(width-1)))
+		}
+		band.WriteString(m.powerStatusStyle(history[idx].Status).Render("▀"))
+	}
+
+	legend := []string{
+		m.powerStatusStyle(core.PowerStatusZenMode).Render("■ Zen"),
+		m.powerStatusStyle(core.PowerStatusFullPower).Render("■ Full Power"),
+		m.powerStatusStyle(core.PowerStatusGoodFlow).Render("■ Good Flow"),
+		m.powerStatusStyle(core.PowerStatusFatigue).Render("■ Fatigue"),
+		m.powerStatusStyle(core.PowerStatusBurnout).Render("■ Burnout"),
+	}
+
+	// Time axis from session start to the last snapshot
+	start := formatDuration(history[0].Timestamp.Sub(m.session.MPI().SessionStart()))
+	end := formatDuration(history[len(history)-1].Timestamp.Sub(m.session.MPI().SessionStart()))
+	gap := width - len(start) - len(end)
+	if gap < 1 {
+		gap = 1
+	}
+	timeAxis := strings.Repeat(" ", 9) + start + strings.Repeat(" ", gap) + end
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.theme.PaneTitle.Render("💪 Power Over Time"),
+		RenderLineChart(m.theme, values, width, 6),
+		m.theme.ChartAxis.Render("  status│")+band.String(),
+		m.theme.ChartAxis.Render(timeAxis),
+		strings.Join(legend, "  "),
+	)
 }
 
 // renderMetrics renders the real-time metrics panel
 func (m *Model) renderMetrics() string {
-	if !m.timer.IsRunning() && m.timer.Elapsed() == 0 {
+	if !m.session.IsRunning() && m.session.Elapsed() == 0 {
 		// Show default metrics before starting
 		metrics := []string{
 			"⏱️  Time: 00:00",
 			"🎯 Accuracy: --%",
-			"⚡ WPM: --",
+			fmt.Sprintf("⚡ WPM (%s): --", m.session.WPMDefinition()),
 			"📊 CPM: --",
 			"❌ Mistakes: 0",
 		}
+		if m.pacer != nil {
+			metrics = append(metrics, fmt.Sprintf("🎵 Pace %.0f: --", m.pacer.TargetWPM()))
+		}
 		content := strings.Join(metrics, " │ ")
 		return m.theme.MetricsPanel.Width(m.width - 2).Render(content)
 	}
 
-	stats := m.metrics.GetCurrentStats()
-	elapsed := m.timer.Elapsed()
+	stats := m.session.CurrentStats()
+	elapsed := m.session.Elapsed()
 
 	timeStr := formatDuration(elapsed)
 
 	metrics := []string{
 		fmt.Sprintf("⏱️  Time: %s", timeStr),
 		fmt.Sprintf("🎯 Accuracy: %.1f%%", stats.Accuracy),
-		fmt.Sprintf("⚡ WPM: %.0f", stats.WPM),
+		fmt.Sprintf("⚡ WPM (%s): %.0f", stats.WPMDefinition, stats.WPM),
 		fmt.Sprintf("📊 CPM: %.0f", stats.CPM),
 		fmt.Sprintf("❌ Mistakes: %d", stats.Mistakes),
 	}
+	if m.pacer != nil {
+		metrics = append(metrics, m.renderPace(elapsed))
+	}
 
 	content := strings.Join(metrics, " │ ")
 	return m.theme.MetricsPanel.Width(m.width - 2).Render(content)
 }
 
+// pacePosition returns the line and column the target pace has reached,
+// or -1, -1 when pacing is off
+func (m *Model) pacePosition() (int, int) {
+	if m.pacer == nil || m.session.Elapsed() == 0 {
+		return -1, -1
+	}
+	return m.pacer.Position(m.session.TypedLines(), m.session.Elapsed())
+}
+
+// renderPace describes how far ahead of or behind the target pace the user is
+func (m *Model) renderPace(elapsed time.Duration) string {
+	offset := m.pacer.Offset(m.session.TypedCharacters(), elapsed)
+	label := fmt.Sprintf("🎵 Pace %.0f: ", m.pacer.TargetWPM())
+
+	// Within one character of the target counts as on pace
+	if offset > -m.pacer.CharInterval() && offset < m.pacer.CharInterval() {
+		return label + "on pace"
+	}
+	if offset > 0 {
+		return label + fmt.Sprintf("%.1fs ahead", offset.Seconds())
+	}
+	return label + fmt.Sprintf("%.1fs behind", -offset.Seconds())
+}
+
 // renderControls renders the control help
 func (m *Model) renderControls() string {
-	controls := "Ctrl+R: Retry │ Ctrl+U: Upload │ Esc: Menu"
-	return m.theme.Controls.Render(controls)
+	sound := "Mute"
+	if m.muted {
+		sound = "Unmute"
+	}
+	labels := map[core.KeyAction]string{
+		core.ActionPalette:     "Commands",
+		core.ActionRetry:       "Retry",
+		core.ActionOpen:        "Upload",
+		core.ActionSkipLine:    "Skip line",
+		core.ActionRestartLine: "Restart line",
+		core.ActionPowerGraph:  "Power graph",
+		core.ActionKeyboard:    "Keyboard",
+		core.ActionMute:        sound,
+		core.ActionMenu:        "Menu",
+	}
+
+	// Unbound actions are left to the palette
+	var controls []string
+	for _, action := range core.KeyActions {
+		if key := m.keys[action]; key != "" {
+			controls = append(controls, keyLabel(key)+": "+labels[action])
+		}
+	}
+	return m.theme.Controls.Render(strings.Join(controls, " │ "))
+}
+
+// keyLabel formats a key name for display, e.g. "ctrl+r" as "Ctrl+R"
+func keyLabel(key string) string {
+	parts := strings.Split(key, "+")
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
 }
 
 // renderSummary renders the session completion summary
 func (m *Model) renderSummary() string {
+	if m.playlist != nil && m.playlist.finished() {
+		return m.renderPlaylistSummary()
+	}
+
 	title := m.theme.Title.Render("🎉 SyntaxRush Session Complete!")
 
 	// Get MPI final stats
-	mpiStats := m.mpi.GetStats()
-	finalPowerLevel := m.mpi.GetCurrentPowerLevel()
+	mpi := m.session.MPI()
+	mpiStats := mpi.GetStats()
+	finalPowerLevel := mpi.GetCurrentPowerLevel()
+	finalStats := m.session.Stats()
 
 	stats := []string{
 		fmt.Sprintf("📁 File: %s", m.filename),
-		fmt.Sprintf("📄 Lines completed: %d", m.totalLines),
-		fmt.Sprintf("⏱️  Total time: %s", formatDuration(m.finalStats.TotalTime)),
-		fmt.Sprintf("🎯 Final accuracy: %.1f%%", m.finalStats.Accuracy),
-		fmt.Sprintf("⚡ Average WPM: %.1f", m.finalStats.WPM),
-		fmt.Sprintf("📊 Average CPM: %.1f", m.finalStats.CPM),
-		fmt.Sprintf("❌ Total mistakes: %d", m.finalStats.TotalMistakes),
+		fmt.Sprintf("📄 Lines completed: %d", finalStats.LinesCompleted),
+	}
+	if finalStats.LinesSkipped > 0 {
+		stats = append(stats, fmt.Sprintf("⏭️  Lines skipped: %d", finalStats.LinesSkipped))
+	}
+	stats = append(stats,
+		fmt.Sprintf("⏱️  Total time: %s", formatDuration(finalStats.TotalTime)),
+		fmt.Sprintf("🎯 Final accuracy: %.1f%%", finalStats.Accuracy),
+		fmt.Sprintf("⚡ Average WPM: %.1f (%s: %s)", finalStats.WPM, finalStats.WPMDefinition, finalStats.WPMDefinition.Description()),
+		fmt.Sprintf("🚀 Raw WPM: %.1f │ Net WPM: %.1f │ KPM: %.0f", finalStats.RawWPM, finalStats.NetWPM, finalStats.KPM),
+		fmt.Sprintf("📊 Average CPM: %.1f", finalStats.CPM),
+		fmt.Sprintf("❌ Total mistakes: %d", finalStats.TotalMistakes),
+	)
+	if m.session.TimeLimit() > 0 {
+		stats = m.testResults(finalStats)
+	}
+	stats = append(stats, m.renderLineHotspots()...)
+	stats = append(stats, m.renderFingerReport()...)
+
+	stats = append(stats,
 		"",
 		"💪 MUSCLE POWER INDICATOR RESULTS:",
 		fmt.Sprintf("🏆 Final Power State: %s %s", finalPowerLevel.Icon, finalPowerLevel.Message),
@@ -426,7 +748,7 @@ func (m *Model) renderSummary() string {
 		fmt.Sprintf("🎯 Consistency Score: %.1f%%", mpiStats["consistency_score"].(float64)*100),
 		fmt.Sprintf("⌨️  Total Keystrokes: %d", mpiStats["total_keystrokes"]),
 		fmt.Sprintf("⏱️  Avg Keystroke Delay: %dms", mpiStats["avg_keystroke_delay"]),
-	}
+	)
 
 	// Add special achievements
 	if maxStreak, ok := mpiStats["max_streak"].(int); ok && maxStreak > 0 {
@@ -449,79 +771,80 @@ func (m *Model) renderSummary() string {
 	}
 
 	stats = append(stats, "", "🏆 Great job! Keep practicing to improve your speed and accuracy.")
+	if m.playlist != nil {
+		stats = append(stats, m.renderPlaylistProgress()...)
+	}
+
+	if chart := m.renderPowerChart(); chart != "" {
+		stats = append(stats, "", chart)
+	}
 
 	statsContent := strings.Join(stats, "\n")
 	styledStats := m.theme.Summary.Render(statsContent)
 
+	reviewControl := "  D - Review mistakes line by line"
+	if m.showReview {
+		styledStats = m.renderLineReview()
+		reviewControl = "  D - Back to results"
+	}
+
+	retryControl := "  R - Retry this file"
+	if m.redoPass > 0 {
+		retryControl = "  R - Retry these lines"
+	}
 	controls := []string{
 		"",
 		"What's next?",
-		"  R - Retry this file",
+		retryControl,
 		"  U - Upload new file",
+		reviewControl,
+	}
+	if redo := len(m.redoLines()); redo > 0 {
+		controls = append(controls, fmt.Sprintf("  M - Type again only the %d lines with mistakes or skipped", redo))
+	}
+	controls = append(controls,
 		"  Enter/Space - Back to menu",
 		"  Q/Esc - Quit",
+	)
+	if m.playlist != nil {
+		controls = m.playlistControls(reviewControl)
 	}
 
 	controlsContent := strings.Join(controls, "\n")
 	styledControls := m.theme.Text.Render(controlsContent)
 
-	return lipgloss.JoinVertical(lipgloss.Center, title, "", styledStats, styledControls)
-}
-
-// renderFileSelect renders the file selection screen
-func (m *Model) renderFileSelect() string {
-	title := m.theme.Title.Render("📁 SyntaxRush - File Upload")
-
-	content := []string{
-		"Load a code file for typing practice",
-		"",
-		"Quick shortcuts:",
-		"  • 'go' or 'sample.go' - Go calculator example",
-		"  • 'py' or 'sample.py' - Python data processor",
-		"  • 'js' or 'sample.js' - JavaScript task manager",
-		"  • 'cpp' or 'sample.cpp' - C++ grade system",
-		"",
-		"Or enter full/relative path to your file:",
-		"  • ./mycode.go",
-		"  • /home/user/project/main.py",
-		"  • ../examples/demo.js",
-		"",
-		"Supported formats:",
-		"  .go .py .js .ts .jsx .tsx .cpp .c .java .rs",
-		"",
+	if m.message != "" {
+		message := m.theme.Error.Render(m.message)
+		return lipgloss.JoinVertical(lipgloss.Center, title, "", styledStats, styledControls, "", message)
 	}
 
-	// Show the input prompt
-	prompt := "Enter file path: "
+	return lipgloss.JoinVertical(lipgloss.Center, title, "", styledStats, styledControls)
+}
 
-	// Show the current input with cursor
-	inputDisplay := m.fileInput + "█"
-	if m.fileInput == "" {
-		inputDisplay = "█"
+// testResults returns the summary lines of a timed test
+func (m *Model) testResults(finalStats core.SessionStats) []string {
+	results := []string{
+		fmt.Sprintf("📁 File: %s", m.filename),
+		fmt.Sprintf("⏱️  Timed test: %s", formatSeconds(m.session.TimeLimit())),
+		fmt.Sprintf("⚡ WPM: %.1f (5 characters per word)", m.session.StandardWPM()),
+		fmt.Sprintf("🚀 Raw WPM: %.1f │ Net WPM: %.1f │ KPM: %.0f", finalStats.RawWPM, finalStats.NetWPM, finalStats.KPM),
+		fmt.Sprintf("🎯 Accuracy: %.1f%%", finalStats.Accuracy),
+		fmt.Sprintf("⌨️  Characters: %d correct of %d", finalStats.CorrectCharacters, finalStats.TotalCharacters),
+		fmt.Sprintf("❌ Mistakes: %d", finalStats.TotalMistakes),
 	}
 
-	styledPrompt := m.theme.Text.Render(prompt)
-	styledInput := m.theme.InputPane.Render(inputDisplay)
-
-	content = append(content, "")
-
-	// Show error if any
-	if m.fileError != "" {
-		errorMsg := "❌ Error: " + m.fileError
-		content = append(content, errorMsg)
-		content = append(content, "")
+	switch {
+	case m.testRank == 1 && m.testEntries > 1:
+		results = append(results, "🏆 New personal best!")
+	case m.testRank > 0:
+		results = append(results, fmt.Sprintf("🏅 Leaderboard: #%d of %d", m.testRank, m.testEntries))
 	}
+	return results
+}
 
-	content = append(content, "Controls:")
-	content = append(content, "  Enter - Load file (or use sample if empty)")
-	content = append(content, "  Backspace - Delete character")
-	content = append(content, "  Esc - Back to menu")
-
-	styledContent := m.theme.Text.Render(strings.Join(content, "\n"))
-
-	inputSection := lipgloss.JoinHorizontal(lipgloss.Left, styledPrompt, styledInput)
-
-	return lipgloss.JoinVertical(lipgloss.Center, title, "", styledContent, "", inputSection)
+// formatSeconds formats a duration as whole seconds, e.g. "60s"
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d.Seconds()))
 }
 
 // formatDuration formats a duration as MM:SS
@@ -0,0 +1,416 @@
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/vamshi1188/SyntaxRush/core"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Resolver turns a file argument or sample shortcut into a file path
+type Resolver func(name string) string
+
+// Server serves the browser typing UI and its WebSocket session API
+type Server struct {
+	defaultFile string
+	resolve     Resolver
+	parser      *core.Parser
+	history     *core.History
+	definition  core.WPMDefinition
+	upgrader    websocket.Upgrader
+	address     string // Host and port the server listens on, if known
+}
+
+// NewServer creates a web server. history may be nil to disable recording.
+func NewServer(defaultFile string, resolve Resolver, history *core.History) *Server {
+	return &Server{
+		defaultFile: defaultFile,
+		resolve:     resolve,
+		parser:      core.NewParser(),
+		history:     history,
+		definition:  core.DefaultWPMDefinition,
+		// The default CheckOrigin rejects cross-origin pages, so other sites
+		// cannot drive sessions on the user's machine
+		upgrader: websocket.Upgrader{},
+	}
+}
+
+// SetWPMDefinition selects how words are counted for WPM in new sessions
+func (s *Server) SetWPMDefinition(definition core.WPMDefinition) {
+	s.definition = definition
+}
+
+// SetAddress sets the host and port the server listens on. Requests
+// addressed to any other host are rejected, so a page on another domain
+// cannot reach the server by rebinding its DNS name to this address.
+func (s *Server) SetAddress(address string) {
+	s.address = address
+}
+
+// Handler returns the HTTP handler for the UI, history API and WebSocket
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/api/history", s.handleHistory)
+	mux.HandleFunc("/ws", s.handleSocket)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			http.Error(w, "unknown host", http.StatusForbidden)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a request's Host header names the address
+// the server listens on. A loopback address may also be reached as
+// localhost, and a server listening on every interface by any name.
+func (s *Server) allowedHost(host string) bool {
+	if s.address == "" {
+		return true
+	}
+	boundHost, boundPort, err := net.SplitHostPort(s.address)
+	if err != nil {
+		return false
+	}
+	requestHost, requestPort, err := net.SplitHostPort(host)
+	if err != nil {
+		requestHost, requestPort = host, "80"
+	}
+	if requestPort != boundPort {
+		return false
+	}
+
+	bound := net.ParseIP(boundHost)
+	switch {
+	case boundHost == "" || (bound != nil && bound.IsUnspecified()):
+		return true
+	case strings.EqualFold(requestHost, boundHost):
+		return true
+	case boundHost == "localhost" || (bound != nil && bound.IsLoopback()):
+		if strings.EqualFold(requestHost, "localhost") {
+			return true
+		}
+		ip := net.ParseIP(strings.Trim(requestHost, "[]"))
+		return ip != nil && ip.IsLoopback()
+	}
+	return false
+}
+
+// loadable reports whether a browser may load a file: the file the server
+// was started with or a built-in sample. Any other path would let whoever
+// reaches the server read files from the machine.
+func (s *Server) loadable(name string) bool {
+	if name == s.defaultFile {
+		return true
+	}
+	_, ok := core.FindSample(name)
+	return ok
+}
+
+// handleHistory returns all recorded sessions as JSON
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	records := []core.SessionRecord{}
+	if s.history != nil {
+		loaded, err := s.history.Load()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if loaded != nil {
+			records = loaded
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// clientMessage is a command sent by the browser
+type clientMessage struct {
+	Type string `json:"type"` // load, start, key, enter, backspace, tab, paste, reset
+	Key  string `json:"key,omitempty"`
+	File string `json:"file,omitempty"`
+}
+
+// loadMessage tells the browser which code is being practiced
+type loadMessage struct {
+	Type  string   `json:"type"`
+	File  string   `json:"file"`
+	Lines []string `json:"lines"`
+}
+
+// eventMessage forwards a session event to the browser
+type eventMessage struct {
+	Type     string `json:"type"`
+	Event    string `json:"event"`
+	Line     int    `json:"line"`
+	Correct  bool   `json:"correct,omitempty"`
+	Mistake  bool   `json:"mistake,omitempty"`
+	Input    string `json:"input,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Perfect  bool   `json:"perfect,omitempty"`
+}
+
+// stateMessage is a snapshot of the session sent after every change
+type stateMessage struct {
+	Type        string       `json:"type"`
+	CurrentLine int          `json:"current_line"`
+	Input       string       `json:"input"`
+	Running     bool         `json:"running"`
+	Finished    bool         `json:"finished"`
+	ElapsedMS   int64        `json:"elapsed_ms"`
+	WPM         float64      `json:"wpm"`
+	Definition  string       `json:"wpm_definition"`
+	CPM         float64      `json:"cpm"`
+	Accuracy    float64      `json:"accuracy"`
+	Mistakes    int          `json:"mistakes"`
+	Power       powerMessage `json:"power"`
+}
+
+// powerMessage carries the muscle power indicator state
+type powerMessage struct {
+	Status     int     `json:"status"`
+	Percentage float64 `json:"percentage"`
+	Message    string  `json:"message"`
+	Color      string  `json:"color"`
+	Streak     int     `json:"streak"`
+	MaxStreak  int     `json:"max_streak"`
+	Peak       float64 `json:"peak"`
+}
+
+// client is one browser connection with its own typing session
+type client struct {
+	server  *Server
+	conn    *websocket.Conn
+	session *core.Session
+	file    string
+
+	mu      sync.Mutex // Guards the session and writes to conn
+	pending []core.Event
+}
+
+// handleSocket upgrades the request and runs a session for the browser
+func (s *Server) handleSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	c := &client{
+		server:  s,
+		conn:    conn,
+		session: core.NewSession(nil),
+	}
+	c.session.OnEvent(func(event core.Event) {
+		c.pending = append(c.pending, event)
+	})
+
+	c.mu.Lock()
+	err = c.load(s.defaultFile)
+	c.mu.Unlock()
+	if err != nil {
+		c.sendError(err)
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go c.tick(done)
+
+	for {
+		var msg clientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+		c.mu.Lock()
+		err := c.handle(msg)
+		c.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// tick pushes live metrics once a second while the session is running
+func (c *client) tick(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			if c.session.IsRunning() {
+				c.session.UpdateMetrics()
+				c.sendState()
+			}
+			c.mu.Unlock()
+		}
+	}
+}
+
+// handle applies a browser command to the session. Must hold c.mu.
+func (c *client) handle(msg clientMessage) error {
+	switch msg.Type {
+	case "load":
+		if !c.server.loadable(msg.File) {
+			return c.sendError(fmt.Errorf("%s is not available; only the served file and the built-in samples can be loaded", msg.File))
+		}
+		if err := c.load(msg.File); err != nil {
+			return c.sendError(err)
+		}
+		return nil
+	case "start":
+		c.session.Reset()
+		c.session.Start()
+	case "reset":
+		c.session.Reset()
+	case "key":
+		if runes := []rune(msg.Key); len(runes) == 1 {
+			c.session.TypeRune(runes[0])
+		}
+	case "enter":
+		c.session.CompleteLine()
+	case "backspace":
+		c.session.Backspace()
+	case "tab":
+		// Tab is only typed where the line has one
+		if c.session.NextChar() == '\t' {
+			c.session.TypeRune('\t')
+		}
+	case "paste":
+		c.session.Paste()
+	}
+
+	if err := c.flushEvents(); err != nil {
+		return err
+	}
+	return c.sendState()
+}
+
+// sendError shows an error in the browser
+func (c *client) sendError(err error) error {
+	return c.conn.WriteJSON(map[string]string{"type": "error", "message": err.Error()})
+}
+
+// load resolves and parses a file and starts a fresh session on it
+func (c *client) load(name string) error {
+	path := c.server.resolve(name)
+	content, err := c.server.parser.ParseFile(path)
+	if err != nil {
+		return err
+	}
+
+	c.file = path
+	c.session.SetWPMDefinition(c.server.definition, core.LanguageForFile(path))
+	c.session.SetLines(strings.Split(content, "\n"))
+	c.pending = nil
+
+	if err := c.conn.WriteJSON(loadMessage{
+		Type:  "load",
+		File:  filepath.Base(path),
+		Lines: c.session.Lines(),
+	}); err != nil {
+		return err
+	}
+	return c.sendState()
+}
+
+// flushEvents forwards queued session events and records finished sessions
+func (c *client) flushEvents() error {
+	events := c.pending
+	c.pending = nil
+
+	var saveErr error
+
+	for _, event := range events {
+		msg := eventMessage{Type: "event", Line: event.Line}
+		switch event.Type {
+		case core.EventKeyTyped:
+			msg.Event = "key_typed"
+			msg.Correct = event.Correct
+			msg.Mistake = event.NewMistake || event.Backspace
+		case core.EventLineCompleted:
+			msg.Event = "line_completed"
+			msg.Input = event.Input
+			msg.Expected = event.Expected
+			msg.Perfect = event.Perfect
+		case core.EventSessionFinished:
+			msg.Event = "session_finished"
+			// Recorded before the browser is told, so its history includes it
+			if c.server.history != nil {
+				saveErr = c.server.history.Append(core.NewSessionRecord(c.file, c.session))
+			}
+		}
+
+		if err := c.conn.WriteJSON(msg); err != nil {
+			return err
+		}
+		if saveErr != nil {
+			if err := c.sendError(fmt.Errorf("could not save session history: %v", saveErr)); err != nil {
+				return err
+			}
+			saveErr = nil
+		}
+	}
+	return nil
+}
+
+// sendState writes a snapshot of the session to the browser
+func (c *client) sendState() error {
+	stats := c.session.CurrentStats()
+	if c.session.IsFinished() {
+		final := c.session.Stats()
+		stats.WPM = final.WPM
+		stats.CPM = final.CPM
+		stats.Accuracy = final.Accuracy
+		stats.Mistakes = final.TotalMistakes
+	}
+
+	mpi := c.session.MPI()
+	level := mpi.GetCurrentPowerLevel()
+	mpiStats := mpi.GetStats()
+	power := powerMessage{
+		Status:     int(level.Status),
+		Percentage: level.Percentage,
+		Message:    level.Message,
+		Color:      level.Color,
+	}
+	power.Streak, _ = mpiStats["correct_streak"].(int)
+	power.MaxStreak, _ = mpiStats["max_streak"].(int)
+	power.Peak, _ = mpiStats["peak_power"].(float64)
+
+	return c.conn.WriteJSON(stateMessage{
+		Type:        "state",
+		CurrentLine: c.session.CurrentLineIndex(),
+		Input:       c.session.Input(),
+		Running:     c.session.IsRunning(),
+		Finished:    c.session.IsFinished(),
+		ElapsedMS:   c.session.Elapsed().Milliseconds(),
+		WPM:         stats.WPM,
+		Definition:  string(c.session.WPMDefinition()),
+		CPM:         stats.CPM,
+		Accuracy:    stats.Accuracy,
+		Mistakes:    stats.Mistakes,
+		Power:       power,
+	})
+}